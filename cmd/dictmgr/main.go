@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer"
 )
@@ -16,6 +17,11 @@ func main() {
 	dictPath := os.Args[1]
 	command := os.Args[2]
 
+	// Validate before loading, since loading rebuilds a stale FST
+	if command == "validate" {
+		os.Exit(validate(dictPath))
+	}
+
 	dict, err := tokenizer.NewDictionary(dictPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading dictionary: %v\n", err)
@@ -36,6 +42,10 @@ func main() {
 			}
 			fmt.Printf("Added: %s\n", word)
 		}
+		if err := dict.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rebuilding FST: %v\n", err)
+			os.Exit(1)
+		}
 		fmt.Printf("Total words: %d\n", dict.WordCount())
 
 	case "remove":
@@ -50,6 +60,10 @@ func main() {
 			}
 			fmt.Printf("Removed: %s\n", word)
 		}
+		if err := dict.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rebuilding FST: %v\n", err)
+			os.Exit(1)
+		}
 		fmt.Printf("Total words: %d\n", dict.WordCount())
 
 	case "contains":
@@ -65,6 +79,15 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "search":
+		if len(os.Args) < 4 {
+			fmt.Println("Error: search requires a pattern")
+			os.Exit(1)
+		}
+		for _, word := range dict.Search(os.Args[3]) {
+			fmt.Println(word)
+		}
+
 	case "rebuild":
 		if err := dict.RebuildFST(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error rebuilding FST: %v\n", err)
@@ -72,9 +95,36 @@ func main() {
 		}
 		fmt.Printf("FST rebuilt. Total words: %d\n", dict.WordCount())
 
+	case "export":
+		if len(os.Args) < 4 {
+			fmt.Println("Error: export requires an output file")
+			os.Exit(1)
+		}
+		if err := dict.Export(os.Args[3]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d words to %s\n", dict.WordCount(), os.Args[3])
+
+	case "import":
+		if len(os.Args) < 4 {
+			fmt.Println("Error: import requires an input file")
+			os.Exit(1)
+		}
+		added, err := dict.Import(os.Args[3])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing '%s': %v\n", os.Args[3], err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d new words. Total words: %d\n", added, dict.WordCount())
+
 	case "stats":
 		fmt.Printf("Dictionary: %s\n", dictPath)
 		fmt.Printf("Word count: %d\n", dict.WordCount())
+		fmt.Printf("Dirty: %t\n", dict.IsDirty())
+		if len(os.Args) > 3 && os.Args[3] == "-verbose" {
+			printStats(dict.Stats())
+		}
 
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
@@ -83,6 +133,62 @@ func main() {
 	}
 }
 
+// validate reports words present in only one of the text file and the FST
+// and returns the exit code: 0 if they agree, 1 otherwise.
+func validate(dictPath string) int {
+	missingFromFST, missingFromText, err := tokenizer.ValidateDictionaryFiles(dictPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error validating dictionary: %v\n", err)
+		return 1
+	}
+
+	for _, word := range missingFromFST {
+		fmt.Printf("missing from FST:  %s\n", word)
+	}
+	for _, word := range missingFromText {
+		fmt.Printf("missing from text: %s\n", word)
+	}
+	if len(missingFromFST) > 0 || len(missingFromText) > 0 {
+		fmt.Printf("Dictionary out of sync: %d missing from FST, %d missing from text file. Run rebuild to fix.\n",
+			len(missingFromFST), len(missingFromText))
+		return 1
+	}
+
+	fmt.Println("Dictionary text file and FST are consistent")
+	return 0
+}
+
+// printStats prints the length histogram and the most common prefixes and
+// suffixes of a dictionary.
+func printStats(stats tokenizer.DictStats) {
+	fmt.Printf("Mean length: %.1f\n", stats.MeanLength)
+	fmt.Println()
+	fmt.Println("Length histogram:")
+	peak := 0
+	for _, count := range stats.LengthHistogram {
+		peak = max(peak, count)
+	}
+	for length, count := range stats.LengthHistogram {
+		if count == 0 {
+			continue
+		}
+		fmt.Printf("  %3d %7d %s\n", length, count, strings.Repeat("#", (count*40+peak-1)/peak))
+	}
+	for _, affixes := range []struct {
+		title  string
+		counts []tokenizer.AffixCount
+	}{
+		{"Top prefixes:", stats.TopPrefixes},
+		{"Top suffixes:", stats.TopSuffixes},
+	} {
+		fmt.Println()
+		fmt.Println(affixes.title)
+		for _, a := range affixes.counts {
+			fmt.Printf("  %-6s %7d\n", a.Affix, a.Count)
+		}
+	}
+}
+
 func printUsage() {
 	fmt.Println("Usage: dictmgr <dictionary.txt> <command> [args...]")
 	fmt.Println()
@@ -90,6 +196,11 @@ func printUsage() {
 	fmt.Println("  add <word> [word...]    Add words to dictionary")
 	fmt.Println("  remove <word> [word...] Remove words from dictionary")
 	fmt.Println("  contains <word>         Check if word exists")
+	fmt.Println("  search <pattern>        List words matching a * / ? wildcard pattern")
 	fmt.Println("  rebuild                 Rebuild FST from text file")
-	fmt.Println("  stats                   Show dictionary statistics")
+	fmt.Println("  export <out.txt>        Write the sorted, deduplicated word list")
+	fmt.Println("  import <in.txt>         Add all words from a file (one FST rebuild)")
+	fmt.Println("  validate                Check that the text file and FST agree")
+	fmt.Println("  stats [-verbose]        Show dictionary statistics; -verbose adds length")
+	fmt.Println("                          histogram and common prefixes/suffixes")
 }
@@ -3,31 +3,41 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer"
+	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer/bundled"
 )
 
 const (
 	iterations = 100000
 	warmup     = 1000
-	boxWidth   = 62
+	boxWidth   = 86
 
 	// ANSI color codes
 	colorReset  = "\033[0m"
 	colorCyan   = "\033[36m"
 	colorGreen  = "\033[32m"
 	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
 	colorDim    = "\033[2m"
 )
 
 var line = strings.Repeat("─", boxWidth)
 
 func main() {
-	dictPath := "dictionaries/german_compound_word_components.txt"
+	var dictPath string
 	if len(os.Args) > 1 {
 		dictPath = os.Args[1]
+	} else {
+		path, err := bundled.DictionaryPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating dictionary: %v\n", err)
+			os.Exit(1)
+		}
+		dictPath = path
 	}
 
 	// Load tokenizer
@@ -35,6 +45,7 @@ func main() {
 	start := time.Now()
 	tok, err := tokenizer.NewTokenizer(dictPath, tokenizer.Config{
 		Cache:             true,
+		CacheSize:         tokenizer.CacheSize,
 		LowercaseOriginal: true,
 		Normalizers: tokenizer.NormalizerConfig{
 			NFKDDecompose:        true,
@@ -55,6 +66,7 @@ func main() {
 	fmt.Printf("done (%d words in %v)\n", tok.DictionaryWordCount(), time.Since(start).Round(time.Millisecond))
 	fmt.Printf("Iterations: %d (warmup: %d)\n", iterations, warmup)
 	fmt.Println("Reference: 1 second = 1,000,000,000 ns")
+	fmt.Println("Allocations and bytes are heap allocations per operation")
 	fmt.Println()
 
 	// Test data
@@ -133,14 +145,19 @@ func bench(name string, fn func()) {
 		fn()
 	}
 
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
 	start := time.Now()
 	for i := 0; i < iterations; i++ {
 		fn()
 	}
 	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
 
 	opsPerSec := float64(iterations) / elapsed.Seconds()
 	nsPerOp := float64(elapsed.Nanoseconds()) / float64(iterations)
+	allocsPerOp := float64(after.Mallocs-before.Mallocs) / float64(iterations)
+	bytesPerOp := float64(after.TotalAlloc-before.TotalAlloc) / float64(iterations)
 
 	// Truncate name if too long
 	displayName := name
@@ -149,14 +166,17 @@ func bench(name string, fn func()) {
 	}
 
 	// Format with colors - build plain string for padding, colored for display
-	plain := fmt.Sprintf("  %-26s %10.0f ops/sec %8.0f ns", displayName, opsPerSec, nsPerOp)
+	plain := fmt.Sprintf("  %-26s %10.0f ops/sec %8.0f ns %6.1f allocs %8.0f B",
+		displayName, opsPerSec, nsPerOp, allocsPerOp, bytesPerOp)
 	padded := padLine(plain)
 
 	// Now colorize the padded string
-	colored := fmt.Sprintf("  %-26s %s%10.0f%s ops/sec %s%8.0f%s ns",
+	colored := fmt.Sprintf("  %-26s %s%10.0f%s ops/sec %s%8.0f%s ns %s%6.1f%s allocs %s%8.0f%s B",
 		displayName,
 		colorGreen, opsPerSec, colorReset,
-		colorYellow, nsPerOp, colorReset)
+		colorYellow, nsPerOp, colorReset,
+		colorBlue, allocsPerOp, colorReset,
+		colorBlue, bytesPerOp, colorReset)
 
 	// Calculate how much padding we added
 	extraPad := len(padded) - len(plain)
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -11,40 +13,65 @@ import (
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: tokenize <dictionary_path> [text]")
-		fmt.Println("       tokenize <dictionary_path>          (interactive mode)")
+	trace := flag.Bool("trace", false, "print each normalization step per word")
+	configPath := flag.String("config", "", "load tokenizer config from a JSON file")
+	inputPath := flag.String("file", "", "tokenize each line of a file (- for stdin)")
+	ndjson := flag.Bool("ndjson", false, "with -file, write one JSON array of tokens per line")
+	structured := flag.Bool("structured", false, "write tokens as JSON objects with their kind (original, segment, ...)")
+	applyToggles := normalizerFlags(flag.CommandLine)
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: tokenize [-trace] [-config file.json] <dictionary_path> [text]")
+		fmt.Println("       tokenize [-trace] [-config file.json] <dictionary_path>          (interactive mode)")
+		fmt.Println("       tokenize [-config file.json] <dictionary_path> -file input.txt [-ndjson]")
+		fmt.Println()
+		fmt.Println("With -structured, JSON output lists tokens as {\"token\": ..., \"kind\": ...} objects.")
+		fmt.Println()
+		fmt.Println("Normalizer steps default to DefaultConfig (or -config) and can be toggled, e.g. -no-stem -no-eszett.")
+		fmt.Println("Run with -h for the full list of flags.")
 		os.Exit(1)
 	}
 
-	dictPath := os.Args[1]
-
-	tok, err := tokenizer.NewTokenizer(dictPath, tokenizer.Config{
-		Cache:             true,
-		LowercaseOriginal: true,
-		Normalizers: tokenizer.NormalizerConfig{
-			NFKDDecompose:        true,
-			RemoveControlChars:   true,
-			Lowercase:            true,
-			NormalizeQuotes:      true,
-			ExpandLigatures:      true,
-			ConvertEszett:        true,
-			RemoveCombiningMarks: true,
-			StemGerman:           true,
-		},
-	})
+	// Allow flags after the dictionary path as well as before it
+	dictPath := flag.Arg(0)
+	flag.CommandLine.Parse(flag.Args()[1:])
+
+	cfg := tokenizer.DefaultConfig()
+	if *configPath != "" {
+		loaded, err := loadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		cfg = loaded
+	}
+	applyToggles(&cfg.Normalizers)
+
+	tok, err := tokenizer.NewTokenizer(dictPath, cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading dictionary: %v\n", err)
 		os.Exit(1)
 	}
 	defer tok.Close()
 
+	// Tokenize a file line by line
+	if *inputPath != "" {
+		if err := tokenizeFile(tok, *inputPath, os.Stdout, *ndjson, *structured); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// If text provided as argument, tokenize and exit
-	if len(os.Args) > 2 {
-		text := strings.Join(os.Args[2:], " ")
-		tokens := tok.Tokenize(text)
-		output, _ := json.Marshal(tokens)
+	if flag.NArg() > 0 {
+		text := strings.Join(flag.Args(), " ")
+		output, _ := marshalTokens(tok, text, *structured)
 		fmt.Println(string(output))
+		if *trace {
+			printTrace(tok, text, "")
+		}
 		return
 	}
 
@@ -65,8 +92,145 @@ func main() {
 			continue
 		}
 
-		tokens := tok.Tokenize(text)
-		output, _ := json.Marshal(tokens)
-		fmt.Printf("  %s\n\n", output)
+		output, _ := marshalTokens(tok, text, *structured)
+		fmt.Printf("  %s\n", output)
+		if *trace {
+			printTrace(tok, text, "  ")
+		}
+		fmt.Println()
+	}
+}
+
+// normalizerToggle is a command-line flag that sets one normalizer step.
+type normalizerToggle struct {
+	name  string
+	usage string
+	field func(*tokenizer.NormalizerConfig) *bool
+	value bool // Value the field is set to when the flag is given
+}
+
+// normalizerToggles lists the flags for turning normalizer steps off (or, for
+// steps DefaultConfig leaves off, on).
+var normalizerToggles = []normalizerToggle{
+	{"no-nfkd", "disable NFKD decomposition", func(n *tokenizer.NormalizerConfig) *bool { return &n.NFKDDecompose }, false},
+	{"no-control", "keep control characters", func(n *tokenizer.NormalizerConfig) *bool { return &n.RemoveControlChars }, false},
+	{"no-format", "keep soft hyphens and zero-width characters", func(n *tokenizer.NormalizerConfig) *bool { return &n.RemoveFormatChars }, false},
+	{"no-lowercase", "disable lowercasing", func(n *tokenizer.NormalizerConfig) *bool { return &n.Lowercase }, false},
+	{"no-quotes", "disable quote normalization", func(n *tokenizer.NormalizerConfig) *bool { return &n.NormalizeQuotes }, false},
+	{"no-ligatures", "disable ligature expansion", func(n *tokenizer.NormalizerConfig) *bool { return &n.ExpandLigatures }, false},
+	{"no-eszett", "keep ß instead of converting it to ss", func(n *tokenizer.NormalizerConfig) *bool { return &n.ConvertEszett }, false},
+	{"no-combining", "keep combining marks (umlauts survive NFKD)", func(n *tokenizer.NormalizerConfig) *bool { return &n.RemoveCombiningMarks }, false},
+	{"no-stem", "disable stemming", func(n *tokenizer.NormalizerConfig) *bool { return &n.StemGerman }, false},
+	{"digraph", "convert umlauts to digraphs (ä→ae)", func(n *tokenizer.NormalizerConfig) *bool { return &n.UmlautToDigraph }, true},
+	{"collapse-repeats", "shorten letter runs of 3+ to 2", func(n *tokenizer.NormalizerConfig) *bool { return &n.CollapseRepeats }, true},
+}
+
+// normalizerFlags registers the normalizer toggles on fs and returns a
+// function applying the ones given on the command line to a config.
+func normalizerFlags(fs *flag.FlagSet) func(*tokenizer.NormalizerConfig) {
+	set := make([]*bool, len(normalizerToggles))
+	for i, toggle := range normalizerToggles {
+		set[i] = fs.Bool(toggle.name, false, toggle.usage)
+	}
+
+	return func(nc *tokenizer.NormalizerConfig) {
+		for i, toggle := range normalizerToggles {
+			if *set[i] {
+				*toggle.field(nc) = toggle.value
+			}
+		}
+	}
+}
+
+// marshalTokens tokenizes text and encodes the tokens as a JSON array, of
+// strings or, if structured, of {"token", "kind"} objects.
+func marshalTokens(tok *tokenizer.Tokenizer, text string, structured bool) ([]byte, error) {
+	if structured {
+		return json.Marshal(tok.TokenizeTyped(text))
+	}
+	return json.Marshal(tok.Tokenize(text))
+}
+
+// tokenizeFile tokenizes each line of the file at path ("-" for stdin) and
+// writes the results to w.
+func tokenizeFile(tok *tokenizer.Tokenizer, path string, w io.Writer, ndjson, structured bool) error {
+	in := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	return tokenizeLines(tok, in, w, ndjson, structured)
+}
+
+// tokenizeLines streams r line by line, writing one output line per input
+// line: a JSON array of tokens with ndjson, space-separated tokens otherwise.
+// With structured, the JSON array holds TypedToken objects.
+func tokenizeLines(tok *tokenizer.Tokenizer, r io.Reader, w io.Writer, ndjson, structured bool) error {
+	reader := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false) // Keep n-gram markers like "<ha" readable
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if readErr == io.EOF && line == "" {
+			break
+		}
+
+		text := strings.TrimRight(line, "\r\n")
+		if ndjson && structured {
+			typed := tok.TokenizeTyped(text)
+			if typed == nil {
+				typed = []tokenizer.TypedToken{}
+			}
+			if err := enc.Encode(typed); err != nil {
+				return err
+			}
+		} else if tokens := tok.Tokenize(text); ndjson {
+			if tokens == nil {
+				tokens = []string{}
+			}
+			if err := enc.Encode(tokens); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintln(out, strings.Join(tokens, " "))
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return out.Flush()
+}
+
+// loadConfig reads a JSON tokenizer config from path.
+func loadConfig(path string) (tokenizer.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return tokenizer.Config{}, err
+	}
+	defer file.Close()
+	return tokenizer.LoadConfig(file)
+}
+
+// printTrace prints the normalization steps applied to each word in text.
+func printTrace(tok *tokenizer.Tokenizer, text, indent string) {
+	for _, raw := range tokenizer.SplitWords(text) {
+		if raw.Type != tokenizer.TokenWord {
+			continue
+		}
+		fmt.Printf("%s%s\n", indent, raw.Text)
+		for _, step := range tok.NormalizeTrace(raw.Text) {
+			fmt.Printf("%s  %-22s %q\n", indent, step.Name, step.Output)
+		}
 	}
 }
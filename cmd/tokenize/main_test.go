@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer"
+)
+
+func TestTokenizeLines_NDJSON(t *testing.T) {
+	tok, err := tokenizer.NewTokenizer("../../dictionaries/german_compound_word_components.txt", tokenizer.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	input := "Brandschutzkonzept\n\nDas Haus\r\nohne Zeilenende"
+	var out bytes.Buffer
+	if err := tokenizeLines(tok, strings.NewReader(input), &out, true, false); err != nil {
+		t.Fatalf("tokenizeLines() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("tokenizeLines() wrote %d lines, want 4:\n%s", len(lines), out.String())
+	}
+
+	for i, line := range lines {
+		var tokens []string
+		if err := json.Unmarshal([]byte(line), &tokens); err != nil {
+			t.Errorf("line %d = %q is not a JSON string array: %v", i, line, err)
+		}
+		if i == 1 && line != "[]" {
+			t.Errorf("line %d = %q, want [] for an empty input line", i, line)
+		}
+	}
+
+	if !strings.Contains(lines[0], `"konzept"`) {
+		t.Errorf("line 0 = %q, want compound segments", lines[0])
+	}
+}
+
+func TestNormalizerFlags(t *testing.T) {
+	fs := flag.NewFlagSet("tokenize", flag.ContinueOnError)
+	apply := normalizerFlags(fs)
+	if err := fs.Parse([]string{"-no-stem", "-no-eszett", "-digraph"}); err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	cfg := tokenizer.DefaultConfig()
+	apply(&cfg.Normalizers)
+
+	if cfg.Normalizers.StemGerman {
+		t.Error("-no-stem: StemGerman = true, want false")
+	}
+	if cfg.Normalizers.ConvertEszett {
+		t.Error("-no-eszett: ConvertEszett = true, want false")
+	}
+	if !cfg.Normalizers.UmlautToDigraph {
+		t.Error("-digraph: UmlautToDigraph = false, want true")
+	}
+	if !cfg.Normalizers.Lowercase || !cfg.Normalizers.NFKDDecompose {
+		t.Error("Untoggled normalizers should keep their defaults")
+	}
+
+	tok, err := tokenizer.NewTokenizer("../../dictionaries/german_compound_word_components.txt", cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Straße")
+	if !slices.Contains(result, "straße") || slices.Contains(result, "strasse") {
+		t.Errorf("Tokenize(%q) with -no-eszett = %v, want ß kept", "Straße", result)
+	}
+}
+
+func TestTokenizeLines_Structured(t *testing.T) {
+	dictPath := filepath.Join(t.TempDir(), "components.txt")
+	if err := os.WriteFile(dictPath, []byte("brand\nschutz\nwärme\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write dictionary: %v", err)
+	}
+	tok, err := tokenizer.NewTokenizer(dictPath, tokenizer.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	var out bytes.Buffer
+	if err := tokenizeLines(tok, strings.NewReader("Wärme\nBrandschutz\n\n"), &out, true, true); err != nil {
+		t.Fatalf("tokenizeLines() error: %v", err)
+	}
+
+	golden, err := os.ReadFile(filepath.Join("testdata", "structured.golden"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if out.String() != string(golden) {
+		t.Errorf("tokenizeLines() structured output =\n%s\nwant\n%s", out.String(), golden)
+	}
+}
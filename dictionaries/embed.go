@@ -0,0 +1,10 @@
+// Package dictionaries embeds the word lists bundled with the module.
+package dictionaries
+
+import "embed"
+
+// FS holds the bundled compound word components dictionary and
+// abbreviation list.
+//
+//go:embed german_compound_word_components.txt german_abbreviations.txt
+var FS embed.FS
@@ -4,6 +4,7 @@ go 1.24.1
 
 require (
 	github.com/blevesearch/vellum v1.2.0
+	github.com/hashicorp/golang-lru/v2 v2.0.7
 	github.com/kljensen/snowball v0.10.0
 	golang.org/x/text v0.34.0
 )
@@ -11,6 +12,5 @@ require (
 require (
 	github.com/bits-and-blooms/bitset v1.24.2 // indirect
 	github.com/blevesearch/mmap-go v1.2.0 // indirect
-	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	golang.org/x/sys v0.40.0 // indirect
 )
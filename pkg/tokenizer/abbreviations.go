@@ -0,0 +1,76 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Abbreviations is a set of abbreviations with internal periods, such as
+// "z.B." or "u.s.w.", that SplitWordsWithAbbreviations keeps as single word
+// tokens. Matching is case-insensitive.
+type Abbreviations struct {
+	forms  map[string]struct{}
+	maxLen int // Longest abbreviation in runes
+}
+
+// NewAbbreviations creates an abbreviation set from the given forms.
+func NewAbbreviations(forms ...string) *Abbreviations {
+	a := &Abbreviations{forms: make(map[string]struct{}, len(forms))}
+	for _, form := range forms {
+		a.add(form)
+	}
+	return a
+}
+
+// LoadAbbreviations reads an abbreviation list from a text file with one
+// abbreviation per line, in the same format as the dictionary file.
+func LoadAbbreviations(path string) (*Abbreviations, error) {
+	a := NewAbbreviations()
+	if err := readWordFile(path, a.add); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// add inserts a single abbreviation.
+func (a *Abbreviations) add(form string) {
+	form = strings.ToLower(form)
+	a.forms[form] = struct{}{}
+	if n := utf8.RuneCountInString(form); n > a.maxLen {
+		a.maxLen = n
+	}
+}
+
+// Len returns the number of abbreviations in the set.
+func (a *Abbreviations) Len() int {
+	return len(a.forms)
+}
+
+// match returns the length in runes of the longest abbreviation starting at
+// runes[i], or 0 if none matches. Only words directly followed by a period
+// are considered, and an abbreviation ending in a letter or digit must not be
+// followed by another one.
+func (a *Abbreviations) match(runes []rune, i int) int {
+	if a == nil || len(a.forms) == 0 {
+		return 0
+	}
+
+	// Cheap rejection: the first word must be followed by a period
+	j := i
+	for j < len(runes) && getTokenType(runes[j]) == TokenWord {
+		j++
+	}
+	if j == i || j == len(runes) || runes[j] != '.' {
+		return 0
+	}
+
+	for n := min(a.maxLen, len(runes)-i); n > j-i; n-- {
+		if i+n < len(runes) && getTokenType(runes[i+n-1]) == TokenWord && getTokenType(runes[i+n]) == TokenWord {
+			continue
+		}
+		if _, ok := a.forms[strings.ToLower(string(runes[i:i+n]))]; ok {
+			return n
+		}
+	}
+	return 0
+}
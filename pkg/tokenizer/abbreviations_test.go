@@ -0,0 +1,71 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitWordsWithAbbreviations(t *testing.T) {
+	abbrevs := NewAbbreviations("z.B.", "u.s.w.", "i.d.R.", "Nr.")
+
+	tests := []struct {
+		input    string
+		expected []string // Word tokens only
+	}{
+		{"z.B. ein Haus", []string{"z.B.", "ein", "Haus"}},
+		{"Z.b. ein Haus", []string{"Z.b.", "ein", "Haus"}}, // Case-insensitive
+		{"Ein Satz.", []string{"Ein", "Satz"}},
+		{"Häuser, Höfe u.s.w.", []string{"Häuser", "Höfe", "u.s.w."}},
+		{"i.d.R. gut", []string{"i.d.R.", "gut"}},
+		{"Nr. 5", []string{"Nr.", "5"}},
+		{"Nr.5", []string{"Nr.", "5"}},
+		{"z.Bsp.", []string{"z", "Bsp"}}, // "z.B" alone is not listed
+		{"xz.B.", []string{"xz", "B"}},   // Must start at a word boundary
+	}
+
+	for _, tt := range tests {
+		var words []string
+		for _, tok := range SplitWordsWithAbbreviations(tt.input, abbrevs) {
+			if tok.Type == TokenWord {
+				words = append(words, tok.Text)
+			}
+		}
+		if strings.Join(words, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("SplitWordsWithAbbreviations(%q) words = %q, want %q", tt.input, words, tt.expected)
+		}
+	}
+}
+
+func TestSplitWordsWithAbbreviations_Separator(t *testing.T) {
+	tokens := SplitWordsWithAbbreviations("Satz.", NewAbbreviations("z.B."))
+	if len(tokens) != 2 {
+		t.Fatalf("SplitWordsWithAbbreviations(%q) = %v, want word + separator", "Satz.", tokens)
+	}
+	if tokens[0].Text != "Satz" || tokens[0].Type != TokenWord {
+		t.Errorf("tokens[0] = %+v, want word %q", tokens[0], "Satz")
+	}
+	if tokens[1].Text != "." || tokens[1].Type != TokenSeparator || tokens[1].Start != 4 {
+		t.Errorf("tokens[1] = %+v, want separator %q at 4", tokens[1], ".")
+	}
+}
+
+func TestLoadAbbreviations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abbreviations.txt")
+	if err := os.WriteFile(path, []byte("# comment\nz.B.\n\n  usw.  \n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	abbrevs, err := LoadAbbreviations(path)
+	if err != nil {
+		t.Fatalf("LoadAbbreviations() error: %v", err)
+	}
+	if abbrevs.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", abbrevs.Len())
+	}
+
+	if _, err := LoadAbbreviations(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadAbbreviations() on a missing file expected error")
+	}
+}
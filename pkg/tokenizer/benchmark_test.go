@@ -1,6 +1,9 @@
 package tokenizer
 
 import (
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -48,6 +51,24 @@ func BenchmarkTokenize_Sentence(b *testing.B) {
 	}
 }
 
+func BenchmarkTokenizeInto_Sentence(b *testing.B) {
+	dictPath := getTestDictPath()
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		b.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	sentence := "Der Brandschutzkonzept und die Wärmedämmung der Stahlbetondecke"
+
+	var tokens []string
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tokens = tok.TokenizeInto(sentence, tokens[:0])
+	}
+}
+
 func BenchmarkNormalizer_FullPipeline(b *testing.B) {
 	n := NewNormalizer()
 
@@ -57,6 +78,26 @@ func BenchmarkNormalizer_FullPipeline(b *testing.B) {
 	}
 }
 
+func BenchmarkNormalizer_ASCII(b *testing.B) {
+	n := NewNormalizer()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Normalize("Brandschutzkonzept")
+	}
+}
+
+func BenchmarkNormalizer_NonASCII(b *testing.B) {
+	n := NewNormalizer()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Normalize("Brandschutzkonzäpt")
+	}
+}
+
 func BenchmarkCompoundSplitter_Split(b *testing.B) {
 	dictPath := getTestDictPath()
 	dict, err := NewDictionary(dictPath)
@@ -104,3 +145,209 @@ func BenchmarkDictionary_Contains(b *testing.B) {
 		dict.Contains("brand")
 	}
 }
+
+func BenchmarkCompoundSplitter_OverlongWord(b *testing.B) {
+	dictPath := getTestDictPath()
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		b.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+	word := strings.Repeat("brand", 10_000) // 50k runes, rejected by MaxWordLength
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		splitter.Split(word)
+	}
+}
+
+// batchCorpus returns a corpus of short German texts for batch benchmarks.
+func batchCorpus() []string {
+	sentences := []string{
+		"Der Brandschutzkonzept und die Wärmedämmung der Stahlbetondecke",
+		"Wärmedämmverbundsystem",
+		"Die Größe der Straße",
+		"Brandschutzkonzept",
+	}
+	corpus := make([]string, 0, 1000)
+	for i := 0; i < 250; i++ {
+		corpus = append(corpus, sentences...)
+	}
+	return corpus
+}
+
+func BenchmarkTokenize_Sequential(b *testing.B) {
+	dictPath := getTestDictPath()
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		b.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	corpus := batchCorpus()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, text := range corpus {
+			tok.Tokenize(text)
+		}
+	}
+}
+
+func BenchmarkTokenize_Batch(b *testing.B) {
+	dictPath := getTestDictPath()
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		b.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	corpus := batchCorpus()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tok.TokenizeBatch(corpus)
+	}
+}
+
+// longCompounds are long words for comparing split backends.
+var longCompounds = []string{
+	"wärmedämmverbundsystem",
+	"brandschutzkonzeptstahlbetondecke",
+	"straßenbahnhaltestellenüberdachung",
+	"fußballweltmeisterschaftsendspiel",
+}
+
+func BenchmarkCompoundSplitter_LongCompound_FST(b *testing.B) {
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		b.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterNoCache(dict)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range longCompounds {
+			splitter.Split(word)
+		}
+	}
+}
+
+func BenchmarkCompoundSplitter_GreedySplit_LongCompound(b *testing.B) {
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		b.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterNoCache(dict)
+	word := strings.Join(longCompounds, "")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		splitter.greedySplit(word, nil)
+	}
+}
+
+func BenchmarkCompoundSplitter_LongCompound_Trie(b *testing.B) {
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		b.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterNoCache(dict)
+	splitter.trie = newTrieIndex(dict)
+	splitter.trie.current() // Build outside the timed loop
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range longCompounds {
+			splitter.Split(word)
+		}
+	}
+}
+
+// fstCountingLookup counts the Contains calls that reach the FST, i.e. those
+// the dictionary's bloom filter (if any) doesn't reject.
+type fstCountingLookup struct {
+	dict    *Dictionary
+	fstHits int
+}
+
+func (c *fstCountingLookup) Contains(word string) bool {
+	if c.dict.bloom == nil || c.dict.bloom.mayContain(strings.ToLower(word)) {
+		c.fstHits++
+	}
+	return c.dict.Contains(word)
+}
+
+func benchmarkBloomSplit(b *testing.B, bloom bool) {
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		b.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	if bloom {
+		if err := dict.EnableBloomFilter(0); err != nil {
+			b.Fatalf("EnableBloomFilter() error: %v", err)
+		}
+	}
+
+	lookup := &fstCountingLookup{dict: dict}
+	splitter := NewCompoundSplitterNoCache(lookup)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, word := range longCompounds {
+			splitter.Split(word)
+		}
+	}
+	b.ReportMetric(float64(lookup.fstHits)/float64(b.N), "fst-lookups/op")
+}
+
+func BenchmarkCompoundSplitter_LongCompound_NoBloom(b *testing.B) {
+	benchmarkBloomSplit(b, false)
+}
+
+func BenchmarkCompoundSplitter_LongCompound_Bloom(b *testing.B) {
+	benchmarkBloomSplit(b, true)
+}
+
+func BenchmarkDictionary_RebuildFST(b *testing.B) {
+	dict, err := NewDictionary(newTestDictionary(b, syntheticWords(200_000)...))
+	if err != nil {
+		b.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := dict.RebuildFST(); err != nil {
+			b.Fatalf("RebuildFST() error: %v", err)
+		}
+	}
+}
+
+func BenchmarkSortStrings(b *testing.B) {
+	words := syntheticWords(200_000)
+	parts := []int{1}
+	if n := runtime.GOMAXPROCS(0); n > 1 {
+		parts = append(parts, n)
+	}
+	for _, parts := range parts {
+		b.Run(fmt.Sprintf("parts=%d", parts), func(b *testing.B) {
+			buf := make([]string, len(words))
+			for i := 0; i < b.N; i++ {
+				copy(buf, words)
+				sortStrings(buf, parts)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package tokenizer
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// DefaultBloomFalsePositiveRate is the false positive rate used for the
+// dictionary bloom filter when none is given.
+const DefaultBloomFalsePositiveRate = 0.01
+
+// bloomFilter is a fixed-size bloom filter over strings. A negative answer
+// is definite; a positive answer must be confirmed elsewhere.
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64 // Number of bits
+	hashes int
+}
+
+// newBloomFilter sizes a filter for n items at the given false positive rate.
+func newBloomFilter(n int, falsePositiveRate float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	// Optimal sizing: m = -n·ln(p) / ln(2)², k = m/n · ln(2)
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: k,
+	}
+}
+
+// add inserts s into the filter.
+func (b *bloomFilter) add(s string) {
+	h1, h2 := bloomHashes(s)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain reports whether s may have been added. False means definitely not.
+func (b *bloomFilter) mayContain(s string) bool {
+	h1, h2 := bloomHashes(s)
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomHashes derives the two base hashes for double hashing from one
+// 64-bit FNV-1a hash.
+func bloomHashes(s string) (h1, h2 uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	sum := h.Sum64()
+	h1 = sum & 0xffffffff
+	h2 = sum>>32 | 1 // Odd, so successive probes don't repeat early
+	return h1, h2
+}
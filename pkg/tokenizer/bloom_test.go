@@ -0,0 +1,32 @@
+package tokenizer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10000
+	bloom := newBloomFilter(n, 0.01)
+	for i := 0; i < n; i++ {
+		bloom.add(fmt.Sprintf("word%d", i))
+	}
+
+	// No false negatives
+	for i := 0; i < n; i++ {
+		if word := fmt.Sprintf("word%d", i); !bloom.mayContain(word) {
+			t.Fatalf("mayContain(%q) = false for an added word", word)
+		}
+	}
+
+	// False positives stay near the configured rate
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if bloom.mayContain(fmt.Sprintf("other%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / n; rate > 0.03 {
+		t.Errorf("false positive rate = %.3f, want about 0.01", rate)
+	}
+}
@@ -0,0 +1,75 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultDictionaryFile is the file name of the bundled compound word
+// components dictionary.
+const DefaultDictionaryFile = "german_compound_word_components.txt"
+
+// DictionaryPathEnv names the environment variable that overrides the path
+// returned by DefaultDictionaryPath.
+const DictionaryPathEnv = "GERMAN_TOKENIZER_DICTIONARY"
+
+// bundledDictionary is the bundled dictionary's path relative to the
+// repository root.
+var bundledDictionary = filepath.Join("dictionaries", DefaultDictionaryFile)
+
+// ErrDictionaryNotFound is returned by DefaultDictionaryPath when the
+// bundled dictionary is not on disk.
+var ErrDictionaryNotFound = errors.New("bundled dictionary not found")
+
+// DefaultDictionaryPath resolves the bundled dictionary. It returns the
+// first of:
+//   - the path in $GERMAN_TOKENIZER_DICTIONARY, if set
+//   - dictionaries/german_compound_word_components.txt in the working
+//     directory or one of its parents
+//   - the same path next to the executable or in its parent directory,
+//     for binaries installed to bin/
+//
+// It returns ErrDictionaryNotFound otherwise. Programs that run without the
+// repository can fall back to a copy embedded in the binary with
+// bundled.DictionaryPath from pkg/tokenizer/bundled instead.
+func DefaultDictionaryPath() (string, error) {
+	if path := os.Getenv(DictionaryPathEnv); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%s: %w", DictionaryPathEnv, err)
+		}
+		return path, nil
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		for dir := wd; ; dir = filepath.Dir(dir) {
+			if path, ok := bundledIn(dir); ok {
+				return path, nil
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		for _, d := range []string{dir, filepath.Dir(dir)} {
+			if path, ok := bundledIn(d); ok {
+				return path, nil
+			}
+		}
+	}
+
+	return "", ErrDictionaryNotFound
+}
+
+// bundledIn returns the bundled dictionary's path under dir if it exists.
+func bundledIn(dir string) (string, bool) {
+	path := filepath.Join(dir, bundledDictionary)
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path, true
+	}
+	return "", false
+}
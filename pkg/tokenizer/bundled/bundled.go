@@ -0,0 +1,81 @@
+// Package bundled provides the dictionary bundled with the module to
+// programs that run without the repository, such as installed binaries.
+// Importing it embeds the dictionary in the binary, so the tokenizer
+// package itself doesn't.
+package bundled
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/kerem-kaynak/german-tokenizer/dictionaries"
+	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer"
+)
+
+// DictionaryPath resolves the bundled dictionary like
+// tokenizer.DefaultDictionaryPath. If it is not on disk, the copy embedded
+// in the binary is written to the user cache directory on first use.
+//
+// The cached copy lives in a directory named after a hash of the embedded
+// dictionary, so a binary with an updated dictionary extracts a fresh copy,
+// while edits made through Dictionary persist across runs of the same one.
+func DictionaryPath() (string, error) {
+	path, err := tokenizer.DefaultDictionaryPath()
+	if !errors.Is(err, tokenizer.ErrDictionaryNotFound) {
+		return path, err
+	}
+	return extract()
+}
+
+// extract writes the embedded dictionary to the user cache directory unless
+// it is already there, and returns its path.
+func extract() (string, error) {
+	data, err := dictionaries.FS.ReadFile(tokenizer.DefaultDictionaryFile)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	dir := filepath.Join(cacheDir, "german-tokenizer", contentKey(data))
+	path := filepath.Join(dir, tokenizer.DefaultDictionaryFile)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// Write to a temporary file first so a concurrent first run never sees
+	// a partial dictionary
+	tmp, err := os.CreateTemp(dir, tokenizer.DefaultDictionaryFile+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write bundled dictionary: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write bundled dictionary: %w", err)
+	}
+	return path, nil
+}
+
+// contentKey names the cache directory for an embedded dictionary.
+func contentKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,64 @@
+package bundled
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kerem-kaynak/german-tokenizer/dictionaries"
+	"github.com/kerem-kaynak/german-tokenizer/pkg/tokenizer"
+)
+
+func TestDictionaryPath_Embedded(t *testing.T) {
+	cache := t.TempDir()
+	t.Setenv(tokenizer.DictionaryPathEnv, "")
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	t.Chdir(t.TempDir())
+
+	path, err := DictionaryPath()
+	if err != nil {
+		t.Fatalf("DictionaryPath() error: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := dictionaries.FS.ReadFile(tokenizer.DefaultDictionaryFile)
+	if !bytes.Equal(got, want) {
+		t.Errorf("extracted dictionary at %q differs from the embedded one", path)
+	}
+	// The cached copy is keyed on the embedded content
+	if dir := filepath.Base(filepath.Dir(path)); dir != contentKey(want) {
+		t.Errorf("extracted dictionary in %q, want %q", dir, contentKey(want))
+	}
+
+	tok, err := tokenizer.NewTokenizer(path, tokenizer.DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewTokenizer(%q) error: %v", path, err)
+	}
+	tok.Close()
+
+	if again, err := DictionaryPath(); err != nil || again != path {
+		t.Errorf("second DictionaryPath() = %q, %v, want %q", again, err, path)
+	}
+}
+
+func TestDictionaryPath_OnDisk(t *testing.T) {
+	want := filepath.Join(t.TempDir(), "components.txt")
+	if err := os.WriteFile(want, []byte("haus\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(tokenizer.DictionaryPathEnv, want)
+
+	if path, err := DictionaryPath(); err != nil || path != want {
+		t.Errorf("DictionaryPath() = %q, %v, want %q", path, err, want)
+	}
+}
+
+func TestContentKey(t *testing.T) {
+	if contentKey([]byte("haus\n")) == contentKey([]byte("haus\nbau\n")) {
+		t.Error("contentKey() is the same for different dictionaries")
+	}
+}
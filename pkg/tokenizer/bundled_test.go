@@ -0,0 +1,65 @@
+package tokenizer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDictionaryPath(t *testing.T) {
+	t.Setenv(DictionaryPathEnv, "")
+	want, err := filepath.Abs(getTestDictPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := filepath.Dir(filepath.Dir(want))
+
+	for _, dir := range []string{root, filepath.Join(root, "pkg", "tokenizer"), filepath.Join(root, "cmd")} {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			t.Chdir(dir)
+			path, err := DefaultDictionaryPath()
+			if err != nil {
+				t.Fatalf("DefaultDictionaryPath() error: %v", err)
+			}
+			if !sameFile(t, path, want) {
+				t.Errorf("DefaultDictionaryPath() = %q, want %q", path, want)
+			}
+		})
+	}
+}
+
+func TestDefaultDictionaryPath_Env(t *testing.T) {
+	path := newTestDictionary(t, "haus")
+	t.Setenv(DictionaryPathEnv, path)
+	if got, err := DefaultDictionaryPath(); err != nil || got != path {
+		t.Errorf("DefaultDictionaryPath() = %q, %v, want %q", got, err, path)
+	}
+
+	t.Setenv(DictionaryPathEnv, filepath.Join(t.TempDir(), "missing.txt"))
+	if _, err := DefaultDictionaryPath(); err == nil {
+		t.Error("DefaultDictionaryPath() with a missing override succeeded, want error")
+	}
+}
+
+func TestDefaultDictionaryPath_NotFound(t *testing.T) {
+	t.Setenv(DictionaryPathEnv, "")
+	t.Chdir(t.TempDir())
+
+	if path, err := DefaultDictionaryPath(); !errors.Is(err, ErrDictionaryNotFound) {
+		t.Errorf("DefaultDictionaryPath() = %q, %v, want %v", path, err, ErrDictionaryNotFound)
+	}
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	ia, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ia, ib)
+}
@@ -0,0 +1,103 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CaseTag describes the capitalization of a token's source word.
+// German nouns are capitalized, so CaseCapitalized is a cheap noun signal.
+type CaseTag int
+
+const (
+	CaseUnknown     CaseTag = iota // Not tagged (EmitCaseTag disabled)
+	CaseLower                      // "und"
+	CaseCapitalized                // "Haus"
+	CaseUpper                      // "GMBH"
+)
+
+// String returns the tag name.
+func (c CaseTag) String() string {
+	switch c {
+	case CaseLower:
+		return "lower"
+	case CaseCapitalized:
+		return "capitalized"
+	case CaseUpper:
+		return "upper"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectCase classifies the capitalization of word by its letters.
+// Words with no uppercase initial are CaseLower; words of two or more letters
+// that are all uppercase are CaseUpper; other words with an uppercase initial
+// are CaseCapitalized.
+func DetectCase(word string) CaseTag {
+	first, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) {
+		return CaseLower
+	}
+
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return CaseCapitalized
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters > 1 {
+		return CaseUpper
+	}
+	return CaseCapitalized
+}
+
+// TaggedToken is an output token with the case of the word it came from.
+type TaggedToken struct {
+	Text string
+	Case CaseTag
+}
+
+// OutputCase selects the casing of emitted tokens.
+type OutputCase int
+
+const (
+	OutputLower    OutputCase = iota // Lowercase, as normalized: "brand"
+	OutputOriginal                   // Casing of the source text: "Brand", "schutz"
+	OutputTitle                      // Initial letter uppercased: "Brand", "Schutz"
+)
+
+// applyOutputCase recases token, which was derived from source, for mode.
+// With OutputOriginal, a token that is just source lowercased is replaced
+// by source itself; other tokens, changed by normalization, take on the
+// capitalization of source as classified by DetectCase.
+func applyOutputCase(mode OutputCase, token, source string) string {
+	switch mode {
+	case OutputOriginal:
+		if token == strings.ToLower(source) {
+			return source
+		}
+		switch DetectCase(source) {
+		case CaseUpper:
+			return strings.ToUpper(token)
+		case CaseCapitalized:
+			return titleCase(token)
+		}
+	case OutputTitle:
+		return titleCase(token)
+	}
+	return token
+}
+
+// titleCase uppercases the first rune of s.
+func titleCase(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || unicode.IsTitle(r) {
+		return s
+	}
+	return string(unicode.ToTitle(r)) + s[size:]
+}
@@ -0,0 +1,149 @@
+package tokenizer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectCase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected CaseTag
+	}{
+		{"Haus", CaseCapitalized},
+		{"und", CaseLower},
+		{"Übung", CaseCapitalized},
+		{"GMBH", CaseUpper},
+		{"STRAẞE", CaseUpper},
+		{"A", CaseCapitalized},
+		{"iPhone", CaseLower},
+		{"123", CaseLower},
+	}
+
+	for _, tt := range tests {
+		result := DetectCase(tt.input)
+		if result != tt.expected {
+			t.Errorf("DetectCase(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_OutputCase(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+
+	tests := []struct {
+		mode     OutputCase
+		expected []string
+	}{
+		{OutputLower, []string{"brandschutzkonzept", "brand", "schutz", "konzept"}},
+		{OutputOriginal, []string{"Brandschutzkonzept", "Brand", "schutz", "konzept"}},
+		{OutputTitle, []string{"Brandschutzkonzept", "Brand", "Schutz", "Konzept"}},
+	}
+
+	for _, tt := range tests {
+		cfg := testConfig()
+		cfg.LowercaseOriginal = true
+		cfg.OutputCase = tt.mode
+		tok, err := NewTokenizer(dictPath, cfg)
+		if err != nil {
+			t.Fatalf("Failed to create tokenizer: %v", err)
+		}
+
+		result := tok.Tokenize("Brandschutzkonzept")
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) with OutputCase %v = %q, want %q", "Brandschutzkonzept", tt.mode, result, tt.expected)
+		}
+		tok.Close()
+	}
+}
+
+func TestTokenizer_SegmentNormalization(t *testing.T) {
+	dictPath := newTestDictionary(t, "wärme", "dämmung")
+
+	tests := []struct {
+		mode     SegmentNormalization
+		expected []string
+	}{
+		{SegmentFull, []string{"wärmedämmung", "warme", "dammung", "häuser", "hauser"}},
+		{SegmentLowercaseOnly, []string{"wärmedämmung", "wärme", "dämmung", "häuser", "hauser"}},
+		{SegmentNone, []string{"wärmedämmung", "Wärme", "dämmung", "häuser", "hauser"}},
+	}
+
+	for _, tt := range tests {
+		cfg := testConfig()
+		cfg.SegmentNormalization = tt.mode
+		tok, err := NewTokenizer(dictPath, cfg)
+		if err != nil {
+			t.Fatalf("Failed to create tokenizer: %v", err)
+		}
+
+		// Häuser doesn't split and stays normalized in every mode
+		result := tok.Tokenize("Wärmedämmung Häuser")
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) with SegmentNormalization %v = %q, want %q", "Wärmedämmung Häuser", tt.mode, result, tt.expected)
+		}
+		tok.Close()
+	}
+}
+
+func TestSegmentNormalization_JSON(t *testing.T) {
+	cfg, err := LoadConfig(strings.NewReader(`{"segment_normalization": "lowercase"}`))
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.SegmentNormalization != SegmentLowercaseOnly {
+		t.Errorf("SegmentNormalization = %d, want SegmentLowercaseOnly", cfg.SegmentNormalization)
+	}
+	if _, err := LoadConfig(strings.NewReader(`{"segment_normalization": "upper"}`)); err == nil {
+		t.Error("LoadConfig() accepted unknown segment_normalization")
+	}
+	if err := (Config{SegmentNormalization: 7}).Validate(); err == nil {
+		t.Error("Validate() accepted invalid SegmentNormalization")
+	}
+}
+
+func TestApplyOutputCase(t *testing.T) {
+	tests := []struct {
+		mode     OutputCase
+		token    string
+		source   string
+		expected string
+	}{
+		{OutputOriginal, "haus", "Haus", "Haus"},
+		{OutputOriginal, "haus", "haus", "haus"},
+		// Normalized tokens take on the source's capitalization
+		{OutputOriginal, "warme", "Wärme", "Warme"},
+		{OutputOriginal, "strasse", "STRASSE", "STRASSE"},
+		{OutputOriginal, "grosse", "GRÖSSE", "GROSSE"},
+		{OutputOriginal, "und", "und", "und"},
+		{OutputTitle, "und", "und", "Und"},
+		{OutputTitle, "übung", "übung", "Übung"},
+		{OutputTitle, "", "", ""},
+		{OutputLower, "haus", "Haus", "haus"},
+	}
+
+	for _, tt := range tests {
+		result := applyOutputCase(tt.mode, tt.token, tt.source)
+		if result != tt.expected {
+			t.Errorf("applyOutputCase(%d, %q, %q) = %q, want %q", tt.mode, tt.token, tt.source, result, tt.expected)
+		}
+	}
+}
+
+func TestOutputCase_JSON(t *testing.T) {
+	cfg, err := LoadConfig(strings.NewReader(`{"output_case": "original"}`))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if cfg.OutputCase != OutputOriginal {
+		t.Errorf("OutputCase = %d, want OutputOriginal", cfg.OutputCase)
+	}
+
+	if _, err := LoadConfig(strings.NewReader(`{"output_case": "upper"}`)); err == nil {
+		t.Error("LoadConfig() accepted unknown output_case")
+	}
+	if err := (Config{OutputCase: 7}).Validate(); err == nil {
+		t.Error("Validate() accepted invalid OutputCase")
+	}
+}
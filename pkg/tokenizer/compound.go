@@ -1,7 +1,15 @@
 package tokenizer
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -10,6 +18,31 @@ import (
 // At ~100 bytes per entry, 100k entries uses approximately 10MB of memory.
 const CacheSize = 100_000
 
+// DefaultMaxWordLength is the longest word (in runes) the splitter attempts to
+// decompose. Longer words are returned unsplit to bound worst-case work.
+const DefaultMaxWordLength = 64
+
+// DefaultMinSegmentLength is the shortest component (in runes) the splitter
+// matches or accepts unless configured otherwise. Two runes admits short
+// German components such as "ei" and "öl".
+const DefaultMinSegmentLength = 2
+
+// DefaultMaxAllSplits is how many segmentations AllSplits returns unless
+// configured otherwise.
+const DefaultMaxAllSplits = 100
+
+// GermanPrefixes are the verb and noun prefixes PrefixFallback strips from
+// words that don't split, longest first so "zurück" wins over "zu".
+// "ge" is left out: the words it starts are mostly not prefixed at all
+// ("gestern" is no "ge" + "stern"). Replace or extend it before creating
+// splitters, or set SplitterConfig.Prefixes per splitter.
+var GermanPrefixes = []string{
+	"zurück", "wieder", "heraus", "herein", "unter", "durch",
+	"miss", "nach", "über", "ver", "vor", "aus", "auf", "ein", "ent",
+	"zer", "mit", "dar", "her", "hin", "ab", "an", "be", "er",
+	"un", "um", "zu",
+}
+
 // germanSuffixes for validation fallback during segment validation.
 var germanSuffixes = []string{
 	"ungen", "schaft", "heiten", "keiten",
@@ -19,85 +52,688 @@ var germanSuffixes = []string{
 	"e", "s", "n", "t",
 }
 
+// WordLookup is the dictionary backend used by CompoundSplitter. *Dictionary
+// implements it; other sources such as an in-memory set or a remote service
+// can be plugged in by implementing Contains.
+//
+// If a WordLookup's contents can change, it should also implement
+// Generation() uint64, returning a value that changes on every update, so
+// cached splits are recomputed. Lookups without it are treated as immutable.
+type WordLookup interface {
+	Contains(word string) bool
+}
+
+// versionedLookup is a WordLookup that reports when its contents change.
+type versionedLookup interface {
+	Generation() uint64
+}
+
 // CompoundSplitter handles German compound word decomposition.
+// It is safe for concurrent use. Cached splits are invalidated when the
+// dictionary is modified.
+//
+// When a word has several valid segmentations, the result is deterministic:
+// ties are broken in segmentationLess order ("wachstube" with both
+// "wachs"+"tube" and "wach"+"stube" in the dictionary splits into
+// "wachs"+"tube"), or by word frequency if SplitterConfig.Frequencies is
+// set.
 type CompoundSplitter struct {
-	dict  *Dictionary
-	cache *lru.Cache[string, []string]
+	dict          WordLookup
+	cache         *lru.Cache[string, cacheEntry]
+	cacheSize     int
+	maxWordLength int
+	maxSegments   int            // 0 means unlimited
+	minPrefix     int            // Shortest prefix greedySplit matches, in runes
+	minSegment    int            // Shortest segment a split may contain, in runes
+	maxLookups    int            // Candidate checks allowed per split, 0 means unlimited
+	maxAllSplits  int            // Segmentations returned by AllSplits
+	suffixes      []string       // Suffixes stripped by isValidWord
+	prefixes      []string       // Prefixes stripped by splitPrefixed, nil disables the fallback
+	frequencies   map[string]int // Word ranks for breaking ties, nil keeps the greedy choice
+	trie          *trieIndex     // Prefix lookup backend, nil to use the dictionary directly
+	metrics       Metrics        // Nil disables split measurements
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
 }
 
-// NewCompoundSplitter creates a new splitter with dictionary and LRU cache enabled.
-func NewCompoundSplitter(dict *Dictionary) *CompoundSplitter {
-	cache, _ := lru.New[string, []string](CacheSize)
-	return &CompoundSplitter{
-		dict:  dict,
-		cache: cache,
+// CacheStats reports split cache activity since the splitter was created.
+type CacheStats struct {
+	Hits      int // Splits served from the cache
+	Misses    int // Splits computed because the word was absent or stale
+	Evictions int // Entries dropped to make room for new ones
+	Size      int // Current number of cached entries
+}
+
+// cacheEntry is a cached split tagged with the dictionary generation it was computed against.
+type cacheEntry struct {
+	segments   []string
+	generation uint64
+}
+
+// SplitterConfig configures a CompoundSplitter. The zero value is a splitter
+// without cache, using DefaultMaxWordLength and the built-in German suffixes.
+type SplitterConfig struct {
+	CacheSize     int      // Maximum cached splits, 0 disables caching
+	MaxWordLength int      // Longest word (in runes) to decompose, 0 means DefaultMaxWordLength
+	MaxSegments   int      // Reject splits with more segments, 0 means unlimited
+	Suffixes      []string // Suffixes stripped when validating segments, nil means the built-in list
+
+	// PrefixFallback retries words that don't split and aren't dictionary
+	// entries with a known prefix stripped ("vorgehen" → "vor", "gehen"),
+	// the prefix becoming a segment of its own. Prefixes lists them, nil means GermanPrefixes.
+	PrefixFallback bool
+	Prefixes       []string
+
+	// MinPrefixLength is the shortest dictionary prefix matched while
+	// splitting; MinSegmentLength is the shortest segment a split may keep.
+	// Both are in runes, and 0 means DefaultMinSegmentLength.
+	MinPrefixLength  int
+	MinSegmentLength int
+
+	// MaxLookups caps the dictionary candidate checks spent splitting one
+	// word; a word that needs more is returned unsplit. This bounds the work
+	// for degenerate dictionaries. 0 means unlimited.
+	MaxLookups int
+
+	// MaxAllSplits caps the segmentations AllSplits returns (0 means
+	// DefaultMaxAllSplits).
+	MaxAllSplits int
+
+	// Frequencies ranks dictionary words, e.g. by corpus counts, to choose
+	// between segmentations of a word into the same number of segments: at
+	// the first segment where they differ, the more frequent word wins,
+	// then the lexicographically smaller one. With "wach" more frequent
+	// than "wachs", "wachstube" splits into "wach"+"stube". Keys are
+	// lowercase. Nil keeps the greedy choice (segmentationLess order); the
+	// bundled dictionary has no frequencies.
+	Frequencies map[string]int
+
+	TrieBackend bool // Find prefixes with an in-memory rune trie; only used when dict is a *Dictionary
+
+	Metrics Metrics // Receives SplitObserved for every Split, nil disables
+}
+
+// NewCompoundSplitterWithConfig creates a new splitter configured by cfg.
+func NewCompoundSplitterWithConfig(dict WordLookup, cfg SplitterConfig) *CompoundSplitter {
+	c := &CompoundSplitter{
+		dict:          dict,
+		maxWordLength: orDefault(cfg.MaxWordLength, DefaultMaxWordLength),
+		maxSegments:   cfg.MaxSegments,
+		minPrefix:     orDefault(cfg.MinPrefixLength, DefaultMinSegmentLength),
+		minSegment:    orDefault(cfg.MinSegmentLength, DefaultMinSegmentLength),
+		maxLookups:    cfg.MaxLookups,
+		maxAllSplits:  orDefault(cfg.MaxAllSplits, DefaultMaxAllSplits),
+		suffixes:      cfg.Suffixes,
+		frequencies:   cfg.Frequencies,
+		metrics:       cfg.Metrics,
 	}
+	if c.suffixes == nil {
+		c.suffixes = germanSuffixes
+	}
+	if cfg.PrefixFallback {
+		c.prefixes = cfg.Prefixes
+		if c.prefixes == nil {
+			c.prefixes = GermanPrefixes
+		}
+	}
+	if cfg.CacheSize > 0 {
+		c.cache, _ = lru.New[string, cacheEntry](cfg.CacheSize)
+		c.cacheSize = cfg.CacheSize
+	}
+	if d, ok := dict.(*Dictionary); ok && cfg.TrieBackend {
+		c.trie = newTrieIndex(d)
+	}
+	return c
+}
+
+// NewCompoundSplitter creates a new splitter with dictionary and LRU cache enabled.
+func NewCompoundSplitter(dict WordLookup) *CompoundSplitter {
+	return NewCompoundSplitterWithConfig(dict, SplitterConfig{CacheSize: CacheSize})
+}
+
+// NewCompoundSplitterWithCacheSize creates a new splitter whose LRU cache
+// holds at most size entries. A size of 0 disables caching.
+func NewCompoundSplitterWithCacheSize(dict WordLookup, size int) *CompoundSplitter {
+	return NewCompoundSplitterWithConfig(dict, SplitterConfig{CacheSize: size})
 }
 
 // NewCompoundSplitterNoCache creates a new splitter without caching.
 // Use this when memory is constrained or words are rarely repeated.
-func NewCompoundSplitterNoCache(dict *Dictionary) *CompoundSplitter {
-	return &CompoundSplitter{
-		dict:  dict,
-		cache: nil,
+func NewCompoundSplitterNoCache(dict WordLookup) *CompoundSplitter {
+	return NewCompoundSplitterWithConfig(dict, SplitterConfig{})
+}
+
+// Config returns the configuration the splitter was created with.
+func (c *CompoundSplitter) Config() SplitterConfig {
+	return SplitterConfig{
+		CacheSize:        c.cacheSize,
+		MaxWordLength:    c.maxWordLength,
+		MaxSegments:      c.maxSegments,
+		Suffixes:         c.suffixes,
+		PrefixFallback:   c.prefixes != nil,
+		Prefixes:         c.prefixes,
+		MinPrefixLength:  c.minPrefix,
+		MinSegmentLength: c.minSegment,
+		MaxLookups:       c.maxLookups,
+		MaxAllSplits:     c.maxAllSplits,
+		Frequencies:      c.frequencies,
+		TrieBackend:      c.trie != nil,
+		Metrics:          c.metrics,
 	}
 }
 
+// withDictionary returns a splitter with the same settings over dict and an empty cache.
+func (c *CompoundSplitter) withDictionary(dict *Dictionary) *CompoundSplitter {
+	return NewCompoundSplitterWithConfig(dict, c.Config())
+}
+
 // Split attempts to decompose a compound word.
 // Returns segments if successful, or [word] if can't split.
+// Words longer than the maximum word length are returned unsplit.
+// The returned slice may be shared with the cache and must not be modified.
 func (c *CompoundSplitter) Split(word string) []string {
+	if c.metrics == nil {
+		segments, _ := c.split(word)
+		return segments
+	}
+
+	start := time.Now()
+	segments, cached := c.split(word)
+	c.metrics.SplitObserved(time.Since(start), cached)
+	return segments
+}
+
+// split implements Split and reports whether the result came from the cache.
+func (c *CompoundSplitter) split(word string) (segments []string, cached bool) {
 	lower := strings.ToLower(word)
 
+	// Skip pathological input before it reaches the quadratic greedy loop
+	if utf8.RuneCountInString(lower) > c.maxWordLength {
+		return []string{lower}, false
+	}
+
 	// If cache is disabled, compute directly
 	if c.cache == nil {
-		return c.splitUncached(lower)
+		segments, _ := c.splitUncached(lower, false)
+		return segments, false
 	}
 
-	// Check cache first (LRU is thread-safe)
-	if result, ok := c.cache.Get(lower); ok {
-		return result
+	// Read the generation before splitting so a concurrent dictionary update
+	// leaves the new entry marked stale rather than wrongly current.
+	generation := c.generation()
+
+	// Check cache first (LRU is thread-safe), ignoring entries from an older dictionary
+	if entry, ok := c.cache.Get(lower); ok && entry.generation == generation {
+		c.hits.Add(1)
+		return entry.segments, true
 	}
+	c.misses.Add(1)
 
 	// Compute split
-	result := c.splitUncached(lower)
+	result, _ := c.splitUncached(lower, false)
 
 	// Store in cache (evicts oldest if at capacity)
-	c.cache.Add(lower, result)
+	if c.cache.Add(lower, cacheEntry{segments: result, generation: generation}) {
+		c.evictions.Add(1)
+	}
+
+	return result, false
+}
+
+// generation returns the lookup's generation, or 0 if it is immutable.
+func (c *CompoundSplitter) generation() uint64 {
+	if v, ok := c.dict.(versionedLookup); ok {
+		return v.Generation()
+	}
+	return 0
+}
+
+// SplitHead splits word like Split and also returns the index of the head
+// segment: the rightmost segment that is a direct dictionary hit rather than
+// a suffix-stripped match. In German compounds this is usually the head noun
+// ("Stahlbetondecke" → "decke"). headIndex is -1 if no segment is a direct hit.
+func (c *CompoundSplitter) SplitHead(word string) (segments []string, headIndex int) {
+	segments = c.Split(word)
+	for i := len(segments) - 1; i >= 0; i-- {
+		if c.isWordInDict(segments[i]) {
+			return segments, i
+		}
+	}
+	return segments, -1
+}
+
+// WordClass classifies a word by how it relates to the dictionary.
+type WordClass int
+
+const (
+	ClassUnknown  WordClass = iota // Not in the dictionary and doesn't split
+	ClassAtomic                    // A single dictionary component: "haus"
+	ClassCompound                  // Splits into two or more components: "brandschutzkonzept"
+)
+
+// String returns the class name.
+func (c WordClass) String() string {
+	switch c {
+	case ClassAtomic:
+		return "atomic"
+	case ClassCompound:
+		return "compound"
+	default:
+		return "unknown"
+	}
+}
 
+// Classify reports whether word is a compound that Split decomposes, a
+// single dictionary component, or unknown. Components are recognized like
+// split segments, including inflected forms found by suffix stripping
+// ("häuser" via "haus"). A compound stored whole in the dictionary is
+// atomic, since Split keeps it whole.
+func (c *CompoundSplitter) Classify(word string) WordClass {
+	segments := c.Split(word)
+	switch {
+	case len(segments) > 1:
+		return ClassCompound
+	case c.isValidWord(segments[0]):
+		return ClassAtomic
+	default:
+		return ClassUnknown
+	}
+}
+
+// confidentSegmentLength is the segment length, in runes, from which a
+// dictionary match is fully trusted. Short components ("ei", "ort") match
+// by accident far more often than long ones.
+const confidentSegmentLength = 5
+
+// SplitConfidence splits word like Split and scores the result, see
+// Confidence.
+func (c *CompoundSplitter) SplitConfidence(word string) (segments []string, confidence float64) {
+	segments = c.Split(word)
+	return segments, c.Confidence(segments)
+}
+
+// Confidence scores a segmentation between 0 and 1. A split is as
+// trustworthy as its weakest segment: a segment scores 1 from
+// confidentSegmentLength runes on and proportionally less below it
+// ("eisen" → "ei", "sen" scores 0.25), halved if it only matches the
+// dictionary after suffix stripping. Digit runs split off by
+// DigitBoundaries are not scored, and an unsplit word scores 1.
+func (c *CompoundSplitter) Confidence(segments []string) float64 {
+	confidence := 1.0
+	if len(segments) < 2 {
+		return confidence
+	}
+	for _, seg := range segments {
+		if r, _ := utf8.DecodeRuneInString(seg); unicode.IsDigit(r) {
+			continue
+		}
+		score := min(1, float64(utf8.RuneCountInString(seg)-1)/float64(confidentSegmentLength-1))
+		if !c.isWordInDict(seg) {
+			score /= 2
+		}
+		confidence = min(confidence, score)
+	}
+	return confidence
+}
+
+// recursiveSegmentLength is the shortest part (in runes) SplitRecursive
+// accepts when it re-splits a dictionary word. Shorter dictionary entries
+// such as "tau" or "ion" match inside too many unrelated words.
+const recursiveSegmentLength = 4
+
+// SplitRecursive splits word like Split, then keeps decomposing any segment
+// that is itself a compound, even if it is stored whole in the dictionary
+// ("Wärmedämmung" → "wärme", "dämmung"). It returns the finest-grained
+// valid components. Every re-split yields strictly shorter segments, so
+// recursion always terminates.
+//
+// Only dictionary words are re-split, and only into dictionary entries of
+// at least recursiveSegmentLength runes, without suffix stripping, so words
+// like "Tausende" or "Lieferbarkeit" stay whole. A word Split could not
+// decompose is returned unchanged without a second attempt.
+func (c *CompoundSplitter) SplitRecursive(word string) []string {
+	segments := c.Split(word)
+	if len(segments) == 1 {
+		if !c.isWordInDict(segments[0]) {
+			return segments
+		}
+		if sub := c.splitProper(segments[0]); sub != nil && c.confidentResplit(sub) {
+			segments = sub
+		} else {
+			return segments
+		}
+	}
+
+	var result []string
+	for _, seg := range segments {
+		result = append(result, c.SplitRecursive(seg)...)
+	}
 	return result
 }
 
-// splitUncached performs the actual splitting without cache.
-func (c *CompoundSplitter) splitUncached(word string) []string {
-	segments := c.greedySplit(word)
+// confidentResplit reports whether every part of a re-split dictionary
+// word is itself a dictionary entry of at least recursiveSegmentLength runes.
+func (c *CompoundSplitter) confidentResplit(segments []string) bool {
+	for _, seg := range segments {
+		if utf8.RuneCountInString(seg) < recursiveSegmentLength || !c.isWordInDict(seg) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitProper decomposes word into at least two valid segments, trying the
+// longest first component shorter than word itself. It returns nil if word
+// has no such decomposition.
+func (c *CompoundSplitter) splitProper(word string) []string {
+	runes := []rune(word)
+	if len(runes) > c.maxWordLength {
+		return nil
+	}
+	budget := c.newLookupBudget()
+	for length := len(runes) - 1; length >= c.minPrefix; length-- {
+		if !budget.spend() {
+			return nil
+		}
+		prefix := string(runes[:length])
+		if !c.isWordInDict(prefix) {
+			continue
+		}
+		rest, stuckAt := c.greedySplit(string(runes[length:]), budget)
+		if budget.exhausted() {
+			return nil
+		}
+		if stuckAt >= 0 {
+			continue
+		}
+		segments := append([]string{prefix}, rest...)
+		if c.maxSegments > 0 && len(segments) > c.maxSegments {
+			continue
+		}
+		if c.allSegmentsValid(segments) {
+			return segments
+		}
+	}
+	return nil
+}
+
+// AllSplits returns every way to segment word into dictionary components,
+// for finding ambiguous words during dictionary development. Segmentations
+// follow the rules of Split: every segment but the last is a dictionary
+// word, the last may also match after suffix stripping, and MaxSegments and
+// the minimum lengths apply. A word that is itself in the dictionary is
+// listed as a single segment.
+//
+// The result is in segmentationLess order, so a successful Split returns the
+// first entry, and holds at most MaxAllSplits segmentations. It is nil if
+// word can't be segmented. The cache is bypassed.
+func (c *CompoundSplitter) AllSplits(word string) [][]string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > c.maxWordLength {
+		return nil
+	}
+
+	minLength := max(c.minPrefix, c.minSegment)
+	budget := c.newLookupBudget()
+	type state struct{ pos, depth int }
+	dead := make(map[state]bool) // Positions known to have no completion
+
+	var all [][]string
+	var path []string
+	var walk func(pos int) bool
+	walk = func(pos int) bool {
+		if pos == len(runes) {
+			all = append(all, append([]string(nil), path...))
+			return true
+		}
+		if c.maxSegments > 0 && len(path) == c.maxSegments {
+			return false
+		}
+		key := state{pos: pos}
+		if c.maxSegments > 0 {
+			key.depth = len(path)
+		}
+		if dead[key] {
+			return false
+		}
+
+		found := false
+		// Longest segment first yields segmentationLess order
+		for end := len(runes); end-pos >= minLength; end-- {
+			if len(all) >= c.maxAllSplits || !budget.spend() {
+				return true
+			}
+			segment := string(runes[pos:end])
+			valid := false
+			if end == len(runes) {
+				valid = c.isValidWord(segment)
+			} else {
+				valid = c.isWordInDict(segment)
+			}
+			if !valid {
+				continue
+			}
+			path = append(path, segment)
+			if walk(end) {
+				found = true
+			}
+			path = path[:len(path)-1]
+		}
+		if !found {
+			dead[key] = true
+		}
+		return found
+	}
+	walk(0)
+
+	return all
+}
+
+// SplitExplain splits word like Split (bypassing the cache), including the
+// prefix fallback and frequency tie-break, and also returns a human-readable
+// reason for the result. When the word can't be split, the reason says why,
+// e.g. which position no dictionary component matched at, to help decide
+// which component is missing from the dictionary.
+func (c *CompoundSplitter) SplitExplain(word string) (segments []string, reason string) {
+	lower := strings.ToLower(word)
+	if n := utf8.RuneCountInString(lower); n > c.maxWordLength {
+		return []string{lower}, fmt.Sprintf("word too long: %d runes exceeds maximum of %d", n, c.maxWordLength)
+	}
+
+	return c.splitUncached(lower, true)
+}
+
+// unsplitReason explains why the greedy split of word, which got stuck at
+// rune position stuckAt (-1 if it didn't), is not a valid decomposition.
+func (c *CompoundSplitter) unsplitReason(word string, segments []string, stuckAt int) string {
+	if stuckAt >= 0 {
+		rest := string([]rune(word)[stuckAt:])
+		return fmt.Sprintf("no dictionary component matches at position %d (%q)", stuckAt, rest)
+	}
+	if len(segments) == 1 {
+		return "not a compound: word is a single dictionary component"
+	}
+	for _, seg := range segments {
+		if utf8.RuneCountInString(seg) < c.minSegment {
+			return fmt.Sprintf("segment %q is shorter than %d runes", seg, c.minSegment)
+		}
+		if !c.isValidWord(seg) {
+			return fmt.Sprintf("segment %q is not a valid word", seg)
+		}
+	}
+	return ""
+}
+
+// segmentationLess is the tie-break order between valid segmentations of
+// the same word. At the first segment where a and b differ, it prefers the
+// longer one, matching the greedy longest-prefix choice, and then the
+// lexicographically smaller one. A segmentation that is a prefix of the
+// other (fewer segments, same start) comes first. With
+// SplitterConfig.Frequencies, frequencyLess decides first between
+// segmentations with the same number of segments.
+//
+// This is a strict total order, so the choice never depends on map or
+// dictionary iteration order.
+func segmentationLess(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		la, lb := utf8.RuneCountInString(a[i]), utf8.RuneCountInString(b[i])
+		if la != lb {
+			return la > lb
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+// frequencyLess orders segmentations with the same number of segments by
+// SplitterConfig.Frequencies: at the first segment where a and b differ,
+// the more frequent word comes first, then the lexicographically smaller.
+func frequencyLess(frequencies map[string]int, a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if fa, fb := frequencies[a[i]], frequencies[b[i]]; fa != fb {
+			return fa > fb
+		}
+		return a[i] < b[i]
+	}
+	return false
+}
+
+// mostFrequentSplit returns the segmentation of word with as many segments
+// as the greedy split that comes first in frequencyLess order.
+func (c *CompoundSplitter) mostFrequentSplit(word string, greedy []string) []string {
+	best := greedy
+	for _, segments := range c.AllSplits(word) {
+		if len(segments) == len(best) && frequencyLess(c.frequencies, segments, best) {
+			best = segments
+		}
+	}
+	return best
+}
+
+// splitUncached performs the actual splitting without cache. With explain
+// set, it also returns the reason SplitExplain reports; otherwise the
+// reason is empty.
+func (c *CompoundSplitter) splitUncached(word string, explain bool) (segments []string, reason string) {
+	unsplit := []string{word}
+	budget := c.newLookupBudget()
+	segments, stuckAt := c.greedySplit(word, budget)
+
+	// Give up on words that exhausted the lookup budget
+	if budget.exhausted() {
+		if explain {
+			reason = fmt.Sprintf("lookup limit of %d exceeded at position %d", c.maxLookups, stuckAt)
+		}
+		return unsplit, reason
+	}
+
+	// Reject decompositions with implausibly many parts
+	if c.maxSegments > 0 && len(segments) > c.maxSegments {
+		if explain {
+			reason = fmt.Sprintf("too many segments: %d exceeds maximum of %d", len(segments), c.maxSegments)
+		}
+		return unsplit, reason
+	}
 
 	// Validate all segments
 	if c.allSegmentsValid(segments) && len(segments) > 1 {
-		return segments
+		if c.frequencies != nil {
+			segments = c.mostFrequentSplit(word, segments)
+		}
+		if explain {
+			reason = fmt.Sprintf("split into %d segments", len(segments))
+		}
+		return segments, reason
+	}
+	if explain {
+		reason = c.unsplitReason(word, segments, stuckAt)
+	}
+
+	// Strip a common prefix from words the dictionary doesn't know at all
+	if c.prefixes != nil && !c.isValidWord(word) {
+		if prefixed := c.splitPrefixed(word, budget); prefixed != nil {
+			if explain {
+				reason = fmt.Sprintf("split into %d segments after prefix %q", len(prefixed), prefixed[0])
+			}
+			return prefixed, reason
+		}
 	}
 
 	// Fallback: return original word as single segment
-	return []string{word}
+	return unsplit, reason
 }
 
-// greedySplit tries to split word from left to right.
-func (c *CompoundSplitter) greedySplit(word string) []string {
-	var segments []string
-	remaining := word
+// splitPrefixed strips the first matching prefix from the lowercase word
+// and splits the remainder, or keeps it whole if it is itself a dictionary
+// entry; a suffix-stripped match isn't enough. It
+// returns the prefix followed by the remainder's segments, or nil if no
+// prefix leads to a valid split.
+func (c *CompoundSplitter) splitPrefixed(word string, budget *lookupBudget) []string {
+	for _, prefix := range c.prefixes {
+		rest, ok := strings.CutPrefix(word, prefix)
+		if !ok || utf8.RuneCountInString(prefix) < c.minSegment || utf8.RuneCountInString(rest) < c.minSegment {
+			continue
+		}
 
-	for len(remaining) > 0 {
+		remainder, _ := c.greedySplit(rest, budget)
+		if budget.exhausted() {
+			return nil
+		}
+		if len(remainder) < 2 || !c.allSegmentsValid(remainder) {
+			if !c.isWordInDict(rest) {
+				continue
+			}
+			remainder = []string{rest}
+		}
+		if c.maxSegments > 0 && len(remainder)+1 > c.maxSegments {
+			continue
+		}
+		return append([]string{prefix}, remainder...)
+	}
+	return nil
+}
+
+// greedySplit tries to split word from left to right, taking the longest
+// dictionary prefix at each position, which follows segmentationLess order.
+// If no dictionary component matches at some point, or budget runs out, it
+// returns [word] and the rune position where splitting got stuck; otherwise
+// stuckAt is -1.
+func (c *CompoundSplitter) greedySplit(word string, budget *lookupBudget) (segments []string, stuckAt int) {
+	if c.trie != nil {
+		return c.greedySplitPrefixes(word, c.trie.current(), budget)
+	}
+
+	// Byte offset of each rune boundary, so candidates are substrings of
+	// word rather than fresh allocations
+	bounds := make([]int, 0, len(word)+1)
+	for i := range word {
+		bounds = append(bounds, i)
+	}
+	bounds = append(bounds, len(word))
+	n := len(bounds) - 1 // Rune count
+
+	pos := 0
+	for pos < n {
 		found := false
-		runes := []rune(remaining)
 
-		// Try longest match first (minimum 2 chars)
-		for length := len(runes); length >= 2; length-- {
-			prefix := string(runes[:length])
-			rest := string(runes[length:])
+		// Try longest match first (minimum minPrefix runes)
+		for end := n; end-pos >= c.minPrefix; end-- {
+			if !budget.spend() {
+				return []string{word}, pos
+			}
+			prefix := word[bounds[pos]:bounds[end]]
 
 			// For final segment (rest is empty), allow suffix-based matching
 			// For intermediate segments, use strict direct lookup only
 			var isValid bool
-			if len(rest) == 0 {
+			if end == n {
 				isValid = c.isValidWord(prefix)
 			} else {
 				isValid = c.isWordInDict(prefix)
@@ -105,7 +741,7 @@ func (c *CompoundSplitter) greedySplit(word string) []string {
 
 			if isValid {
 				segments = append(segments, prefix)
-				remaining = rest
+				pos = end
 				found = true
 				break
 			}
@@ -113,11 +749,145 @@ func (c *CompoundSplitter) greedySplit(word string) []string {
 
 		if !found {
 			// Can't split further - return original
-			return []string{word}
+			return []string{word}, pos
 		}
 	}
 
-	return segments
+	return segments, -1
+}
+
+// greedySplitPrefixes is greedySplit driven by a PrefixLookup: each position
+// takes one walk for direct matches and one for umlaut-normalized matches
+// instead of a dictionary lookup per candidate length. It returns the same
+// segments as the lookup-based path and charges budget for the same
+// candidate probes, so MaxLookups gives up on the same words.
+func (c *CompoundSplitter) greedySplitPrefixes(word string, prefixes PrefixLookup, budget *lookupBudget) (segments []string, stuckAt int) {
+	runes := []rune(word)
+	pos := 0
+
+	for pos < len(runes) {
+		remaining := runes[pos:]
+
+		// The final segment allows suffix-based matching, as in greedySplit
+		length := 0
+		if len(remaining) >= c.minPrefix {
+			if !budget.spend() {
+				return []string{word}, pos
+			}
+			if c.isValidWord(string(remaining)) {
+				length = len(remaining)
+			}
+		}
+		if length == 0 {
+			length = longestPrefix(prefixes, remaining)
+			// greedySplit probes every shorter length down to the match,
+			// or down to minPrefix if there is none
+			if !budget.spendN(len(remaining) - max(length, c.minPrefix)) {
+				return []string{word}, pos
+			}
+		}
+
+		if length < c.minPrefix {
+			return []string{word}, pos
+		}
+		segments = append(segments, string(remaining[:length]))
+		pos += length
+	}
+
+	return segments, -1
+}
+
+// longestPrefix returns the length of the longest dictionary word, directly
+// or after umlaut normalization, that is a proper prefix of runes.
+func longestPrefix(prefixes PrefixLookup, runes []rune) int {
+	longest := 0
+	keep := func(length int) {
+		if length < len(runes) && length > longest {
+			longest = length
+		}
+	}
+	prefixes.WalkPrefixes(runes, keep)
+
+	// Walk the umlaut-normalized form, mapping lengths back to source runes.
+	// srcLen[i] is the source length once normalized rune i is consumed, or
+	// -1 in the middle of an expansion like ß → ss.
+	var normalized []rune
+	var srcLen []int
+	changed := false
+	for i, r := range runes {
+		fold, ok := umlautFolds[r]
+		if !ok {
+			normalized = append(normalized, r)
+			srcLen = append(srcLen, i+1)
+			continue
+		}
+		changed = true
+		for j, fr := range fold { // Folds are ASCII, so j indexes runes
+			normalized = append(normalized, fr)
+			if j == len(fold)-1 {
+				srcLen = append(srcLen, i+1)
+			} else {
+				srcLen = append(srcLen, -1)
+			}
+		}
+	}
+	if changed {
+		prefixes.WalkPrefixes(normalized, func(length int) {
+			if n := srcLen[length-1]; n > 0 {
+				keep(n)
+			}
+		})
+	}
+
+	return longest
+}
+
+// lookupBudget limits the candidate checks of a single split. A nil budget
+// is unlimited.
+type lookupBudget struct {
+	remaining int
+	exceeded  bool // A check was refused because the budget ran out
+}
+
+// newLookupBudget returns a budget of maxLookups checks, or nil if unlimited.
+func (c *CompoundSplitter) newLookupBudget() *lookupBudget {
+	if c.maxLookups <= 0 {
+		return nil
+	}
+	return &lookupBudget{remaining: c.maxLookups}
+}
+
+// spend uses up one check and reports whether the budget allowed it.
+func (b *lookupBudget) spend() bool {
+	if b == nil {
+		return true
+	}
+	if b.remaining == 0 {
+		b.exceeded = true
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// spendN uses up n checks and reports whether the budget allowed all of
+// them. Like n calls to spend, it uses up what is left when it refuses.
+func (b *lookupBudget) spendN(n int) bool {
+	if b == nil || n <= 0 {
+		return true
+	}
+	if b.remaining < n {
+		b.remaining = 0
+		b.exceeded = true
+		return false
+	}
+	b.remaining -= n
+	return true
+}
+
+// exhausted reports whether a check was refused for lack of budget.
+func (b *lookupBudget) exhausted() bool {
+	return b != nil && b.exceeded
 }
 
 // isWordInDict checks if word exists in dictionary (direct lookup + umlaut normalization only).
@@ -155,7 +925,7 @@ func (c *CompoundSplitter) isValidWord(word string) bool {
 	}
 
 	// Try suffix stripping
-	for _, suffix := range germanSuffixes {
+	for _, suffix := range c.suffixes {
 		if strings.HasSuffix(lower, suffix) {
 			stem := strings.TrimSuffix(lower, suffix)
 			if len([]rune(stem)) >= 2 {
@@ -175,7 +945,7 @@ func (c *CompoundSplitter) isValidWord(word string) bool {
 // allSegmentsValid checks if all segments pass validation.
 func (c *CompoundSplitter) allSegmentsValid(segments []string) bool {
 	for _, seg := range segments {
-		if len([]rune(seg)) < 2 {
+		if len([]rune(seg)) < c.minSegment {
 			return false
 		}
 		if !c.isValidWord(seg) {
@@ -185,17 +955,28 @@ func (c *CompoundSplitter) allSegmentsValid(segments []string) bool {
 	return true
 }
 
-// normalizeUmlauts converts ä→a, ö→o, ü→u, ß→ss.
+// umlautFolds maps each rune normalizeUmlauts replaces to its replacement.
+var umlautFolds = map[rune]string{
+	'ä': "a", 'Ä': "a",
+	'ö': "o", 'Ö': "o",
+	'ü': "u", 'Ü': "u",
+	'ß': "ss", '\u1E9E': "ss",
+}
+
+// umlautReplacer applies umlautFolds to a whole string.
+var umlautReplacer = func() *strings.Replacer {
+	var pairs []string
+	for r, repl := range umlautFolds {
+		pairs = append(pairs, string(r), repl)
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// normalizeUmlauts converts ä→a, ö→o, ü→u, ß/ẞ→ss.
 // Used ONLY for dictionary lookup during compound decomposition.
 // This is SEPARATE from the token normalization pipeline.
 func normalizeUmlauts(s string) string {
-	replacer := strings.NewReplacer(
-		"ä", "a", "Ä", "a",
-		"ö", "o", "Ö", "o",
-		"ü", "u", "Ü", "u",
-		"ß", "ss",
-	)
-	return replacer.Replace(s)
+	return umlautReplacer.Replace(s)
 }
 
 // ClearCache clears the memoization cache.
@@ -213,7 +994,70 @@ func (c *CompoundSplitter) CacheSize() int {
 	return c.cache.Len()
 }
 
+// CacheStats returns the cache hit, miss and eviction counters and the
+// current cache size. All values are zero if caching is disabled.
+func (c *CompoundSplitter) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:      int(c.hits.Load()),
+		Misses:    int(c.misses.Load()),
+		Evictions: int(c.evictions.Load()),
+		Size:      c.CacheSize(),
+	}
+}
+
 // CacheEnabled returns true if caching is enabled.
 func (c *CompoundSplitter) CacheEnabled() bool {
 	return c.cache != nil
 }
+
+// ErrCacheDisabled is returned when importing into a splitter without a cache.
+var ErrCacheDisabled = errors.New("split cache is disabled")
+
+// ExportCache writes the cached splits to w, one per line: the word and its
+// segments, separated by tabs ("brandschutz\tbrand\tschutz"). Words that
+// didn't split have a single segment. Entries are written least recently
+// used first, and entries from before a dictionary change are skipped.
+// Nothing is written if caching is disabled.
+func (c *CompoundSplitter) ExportCache(w io.Writer) error {
+	if c.cache == nil {
+		return nil
+	}
+	generation := c.generation()
+
+	bw := bufio.NewWriter(w)
+	for _, word := range c.cache.Keys() {
+		entry, ok := c.cache.Peek(word)
+		if !ok || entry.generation != generation || strings.ContainsAny(word, "\t\n") {
+			continue
+		}
+		if _, err := bw.WriteString(word + "\t" + strings.Join(entry.segments, "\t") + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportCache adds the splits written by ExportCache to the cache, so a
+// restarted process starts warm. Splits are trusted as they are, so import
+// only a cache exported with the same dictionary and splitter settings.
+// Entries beyond the cache size evict the oldest ones, as with Split.
+func (c *CompoundSplitter) ImportCache(r io.Reader) error {
+	if c.cache == nil {
+		return ErrCacheDisabled
+	}
+	generation := c.generation()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) == 1 && fields[0] == "" {
+			continue
+		}
+		word, segments := fields[0], fields[1:]
+		if len(segments) == 0 || strings.Join(segments, "") != word {
+			return fmt.Errorf("invalid cache entry on line %d: segments don't form %q", line, word)
+		}
+		c.cache.Add(word, cacheEntry{segments: segments, generation: generation})
+	}
+	return scanner.Err()
+}
@@ -1,7 +1,13 @@
 package tokenizer
 
 import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestCompoundSplitter_Split(t *testing.T) {
@@ -110,3 +116,845 @@ func TestNormalizeUmlauts(t *testing.T) {
 		}
 	}
 }
+
+func TestCompoundSplitter_CacheInvalidatedOnDictionaryChange(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	// "konzept" is unknown, so the word can't be split yet
+	if result := splitter.Split("brandschutzkonzept"); len(result) != 1 {
+		t.Fatalf("Split before AddWord = %v, want unsplit", result)
+	}
+
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+
+	// The cached unsplit result must not be served after the dictionary changed
+	result := splitter.Split("brandschutzkonzept")
+	if len(result) != 3 {
+		t.Errorf("Split after AddWord = %v, want [brand schutz konzept]", result)
+	}
+}
+
+func TestCompoundSplitter_MaxWordLength(t *testing.T) {
+	dictPath := getTestDictPath()
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	long := strings.Repeat("brand", 10_000)
+	start := time.Now()
+	result := splitter.Split(long)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Split of %d-rune word took %v", len(long), elapsed)
+	}
+	if len(result) != 1 || result[0] != long {
+		t.Errorf("Split of over-long word returned %d segments, want it unsplit", len(result))
+	}
+	if splitter.CacheSize() != 0 {
+		t.Errorf("Expected over-long word not to be cached, cache size %d", splitter.CacheSize())
+	}
+
+	// Words within the limit are still split
+	splitter.maxWordLength = len("brandschutzkonzept")
+	if result := splitter.Split("brandschutzkonzept"); len(result) != 3 {
+		t.Errorf("Split(%q) = %v, want 3 segments", "brandschutzkonzept", result)
+	}
+}
+
+func TestCompoundSplitter_Classify(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "haus", "brand", "schutz", "konzept"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	tests := []struct {
+		input    string
+		expected WordClass
+	}{
+		{"haus", ClassAtomic},
+		{"Haus", ClassAtomic},
+		// Inflected form of a component
+		{"häuser", ClassAtomic},
+		{"brandschutzkonzept", ClassCompound},
+		{"xyzqwv", ClassUnknown},
+		// Only partly decomposable
+		{"brandxyzqw", ClassUnknown},
+	}
+
+	for _, tt := range tests {
+		if result := splitter.Classify(tt.input); result != tt.expected {
+			t.Errorf("Classify(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCompoundSplitter_SplitConfidence(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "ei", "sen", "brand", "schutz", "haus", "tür"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"brandschutz", 1},
+		{"haustür", 0.5},
+		{"eisen", 0.25},
+		// Unsplit words leave nothing to doubt
+		{"haus", 1},
+		{"xyzqwv", 1},
+	}
+
+	for _, tt := range tests {
+		segments, confidence := splitter.SplitConfidence(tt.input)
+		if confidence != tt.expected {
+			t.Errorf("SplitConfidence(%q) = %q, %v, want confidence %v", tt.input, segments, confidence, tt.expected)
+		}
+	}
+
+	// Suffix-stripped matches are trusted less
+	if got := splitter.Confidence([]string{"brand", "häuser"}); got != 0.5 {
+		t.Errorf("Confidence([brand häuser]) = %v, want 0.5", got)
+	}
+}
+
+func TestCompoundSplitter_PrefixFallback(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "gehen", "brand", "schutz", "geh", "beton", "ton", "abend", "end", "stern"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	if result := NewCompoundSplitterNoCache(dict).Split("vorgehen"); !reflect.DeepEqual(result, []string{"vorgehen"}) {
+		t.Errorf("Split(%q) without PrefixFallback = %q, want it unsplit", "vorgehen", result)
+	}
+
+	splitter := NewCompoundSplitterWithConfig(dict, SplitterConfig{PrefixFallback: true})
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"vorgehen", []string{"vor", "gehen"}},
+		{"Vorgehen", []string{"vor", "gehen"}},
+		// The longest prefix is tried first
+		{"zurückgehen", []string{"zurück", "gehen"}},
+		// The remainder may split further, but an unsplit remainder must
+		// be a dictionary entry, not a suffix-stripped match
+		{"unbrandschutz", []string{"un", "brand", "schutz"}},
+		{"vergehst", []string{"vergehst"}},
+		{"vorxyz", []string{"vorxyz"}},
+		// Dictionary entries are never split at a prefix
+		{"beton", []string{"beton"}},
+		{"abend", []string{"abend"}},
+		{"Abends", []string{"abends"}},
+		{"gestern", []string{"gestern"}},
+		// Words that split without the fallback are unaffected
+		{"brandschutz", []string{"brand", "schutz"}},
+	}
+	for _, tt := range tests {
+		if result := splitter.Split(tt.input); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Split(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	custom := NewCompoundSplitterWithConfig(dict, SplitterConfig{PrefixFallback: true, Prefixes: []string{"nach"}})
+	if result := custom.Split("vorgehen"); !reflect.DeepEqual(result, []string{"vorgehen"}) {
+		t.Errorf("Split(%q) with Prefixes [nach] = %q, want it unsplit", "vorgehen", result)
+	}
+	if result := custom.Split("nachgehen"); !reflect.DeepEqual(result, []string{"nach", "gehen"}) {
+		t.Errorf("Split(%q) with Prefixes [nach] = %q, want [nach gehen]", "nachgehen", result)
+	}
+}
+
+func TestCompoundSplitter_SplitHead(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "stahl", "beton", "decke", "brand", "schutz", "konzept"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	tests := []struct {
+		input     string
+		segments  []string
+		headIndex int
+	}{
+		{"stahlbetondecke", []string{"stahl", "beton", "decke"}, 2},
+		// "konzepte" only matches via suffix stripping, so the head moves left
+		{"brandschutzkonzepte", []string{"brand", "schutz", "konzepte"}, 1},
+		{"beton", []string{"beton"}, 0},
+		{"xyzzy", []string{"xyzzy"}, -1},
+	}
+
+	for _, tt := range tests {
+		segments, head := splitter.SplitHead(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.segments, "|") {
+			t.Errorf("SplitHead(%q) segments = %v, want %v", tt.input, segments, tt.segments)
+		}
+		if head != tt.headIndex {
+			t.Errorf("SplitHead(%q) headIndex = %d, want %d", tt.input, head, tt.headIndex)
+		}
+	}
+}
+
+func TestCompoundSplitter_MaxSegments(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "stahl", "beton", "decke"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterNoCache(dict)
+	if result := splitter.Split("brandstahlbetondecke"); len(result) != 4 {
+		t.Fatalf("Split without limit = %v, want 4 segments", result)
+	}
+
+	splitter.maxSegments = 3
+	result := splitter.Split("brandstahlbetondecke")
+	if len(result) != 1 || result[0] != "brandstahlbetondecke" {
+		t.Errorf("Split with MaxSegments=3 = %v, want unsplit", result)
+	}
+
+	// Words within the limit still split
+	if result := splitter.Split("stahlbetondecke"); len(result) != 3 {
+		t.Errorf("Split(%q) with MaxSegments=3 = %v, want 3 segments", "stahlbetondecke", result)
+	}
+}
+
+func TestCompoundSplitter_SplitExplain(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz", "konzept"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	tests := []struct {
+		input    string
+		segments []string
+		reason   string
+	}{
+		{
+			input:    "brandschutzkonzept",
+			segments: []string{"brand", "schutz", "konzept"},
+			reason:   "split into 3 segments",
+		},
+		{
+			// Unknown middle component
+			input:    "brandxyzkonzept",
+			segments: []string{"brandxyzkonzept"},
+			reason:   `no dictionary component matches at position 5 ("xyzkonzept")`,
+		},
+		{
+			input:    "schutz",
+			segments: []string{"schutz"},
+			reason:   "not a compound: word is a single dictionary component",
+		},
+	}
+
+	for _, tt := range tests {
+		segments, reason := splitter.SplitExplain(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.segments, "|") {
+			t.Errorf("SplitExplain(%q) segments = %v, want %v", tt.input, segments, tt.segments)
+		}
+		if reason != tt.reason {
+			t.Errorf("SplitExplain(%q) reason = %q, want %q", tt.input, reason, tt.reason)
+		}
+		// Segments must agree with Split
+		if split := splitter.Split(tt.input); strings.Join(split, "|") != strings.Join(segments, "|") {
+			t.Errorf("SplitExplain(%q) = %v disagrees with Split = %v", tt.input, segments, split)
+		}
+	}
+}
+
+func TestCompoundSplitter_SplitExplainMatchesSplit(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{
+		"gehen": true, "wachs": true, "tube": true, "wach": true, "stube": true,
+	}}
+	splitter := NewCompoundSplitterWithConfig(lookup, SplitterConfig{
+		PrefixFallback: true,
+		Frequencies:    map[string]int{"wach": 10, "wachs": 1},
+	})
+
+	tests := []struct {
+		input    string
+		segments string
+		reason   string
+	}{
+		{"Vorgehen", "vor|gehen", `split into 2 segments after prefix "vor"`},
+		{"Wachstube", "wach|stube", "split into 2 segments"},
+		{"Vorxyz", "vorxyz", `no dictionary component matches at position 0 ("vorxyz")`},
+	}
+
+	for _, tt := range tests {
+		segments, reason := splitter.SplitExplain(tt.input)
+		if result := strings.Join(segments, "|"); result != tt.segments {
+			t.Errorf("SplitExplain(%q) segments = %q, want %q", tt.input, result, tt.segments)
+		}
+		if reason != tt.reason {
+			t.Errorf("SplitExplain(%q) reason = %q, want %q", tt.input, reason, tt.reason)
+		}
+		if split := splitter.Split(tt.input); strings.Join(split, "|") != strings.Join(segments, "|") {
+			t.Errorf("SplitExplain(%q) = %v disagrees with Split = %v", tt.input, segments, split)
+		}
+	}
+}
+
+func TestCompoundSplitter_CacheStats(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz", "konzept", "stahl", "beton"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterWithCacheSize(dict, 2)
+
+	splitter.Split("brandschutz")   // miss
+	splitter.Split("brandschutz")   // hit
+	splitter.Split("stahlbeton")    // miss
+	splitter.Split("schutzkonzept") // miss, evicts brandschutz
+	splitter.Split("brandschutz")   // miss, evicts stahlbeton
+
+	want := CacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}
+	if got := splitter.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+
+	// Entries from an older dictionary generation count as misses
+	if err := dict.AddWord("decke"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	splitter.Split("brandschutz")
+	if got := splitter.CacheStats(); got.Misses != 5 || got.Hits != 1 {
+		t.Errorf("CacheStats() after AddWord = %+v, want 1 hit and 5 misses", got)
+	}
+
+	// Uncached splitters report nothing
+	uncached := NewCompoundSplitterNoCache(dict)
+	uncached.Split("brandschutz")
+	if got := uncached.CacheStats(); got != (CacheStats{}) {
+		t.Errorf("CacheStats() without cache = %+v, want zero", got)
+	}
+}
+
+func TestCompoundSplitter_CacheStatsConcurrent(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+
+	const workers, iterations = 8, 100
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				splitter.Split("brandschutz")
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := splitter.CacheStats()
+	if stats.Hits+stats.Misses != workers*iterations {
+		t.Errorf("CacheStats() hits+misses = %d, want %d", stats.Hits+stats.Misses, workers*iterations)
+	}
+}
+
+func TestCompoundSplitter_CacheSizeLimit(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz", "konzept", "stahl", "beton"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterWithCacheSize(dict, 3)
+	for _, word := range []string{"brandschutz", "stahlbeton", "schutzkonzept", "betonstahl"} {
+		splitter.Split(word)
+	}
+
+	if splitter.CacheSize() != 3 {
+		t.Errorf("CacheSize() = %d, want 3", splitter.CacheSize())
+	}
+	if splitter.cache.Contains("brandschutz") {
+		t.Error("Oldest entry \"brandschutz\" should have been evicted")
+	}
+	if !splitter.cache.Contains("betonstahl") {
+		t.Error("Newest entry \"betonstahl\" should be cached")
+	}
+
+	// Size 0 behaves like NewCompoundSplitterNoCache
+	disabled := NewCompoundSplitterWithCacheSize(dict, 0)
+	if disabled.CacheEnabled() {
+		t.Error("CacheEnabled() = true for size 0, want false")
+	}
+	disabled.Split("brandschutz")
+	if disabled.CacheSize() != 0 {
+		t.Errorf("CacheSize() = %d for size 0, want 0", disabled.CacheSize())
+	}
+}
+
+// setLookup is an in-memory WordLookup for testing the splitter without a Dictionary.
+type setLookup struct {
+	words      map[string]bool
+	generation uint64
+}
+
+func (s *setLookup) Contains(word string) bool { return s.words[word] }
+func (s *setLookup) Generation() uint64        { return s.generation }
+
+func TestCompoundSplitter_WordLookup(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"brand": true, "schutz": true, "konzept": true}}
+	splitter := NewCompoundSplitter(lookup)
+
+	result := splitter.Split("Brandschutzkonzept")
+	if strings.Join(result, "|") != "brand|schutz|konzept" {
+		t.Errorf("Split(%q) = %v, want [brand schutz konzept]", "Brandschutzkonzept", result)
+	}
+
+	// Umlaut normalization and suffix stripping still apply
+	lookup.words["warme"] = true
+	lookup.words["damm"] = true
+	lookup.generation++
+	result = splitter.Split("wärmedämmung")
+	if strings.Join(result, "|") != "wärme|dämmung" {
+		t.Errorf("Split(%q) = %v, want [wärme dämmung]", "wärmedämmung", result)
+	}
+
+	// Cached results are recomputed after the generation changes
+	delete(lookup.words, "konzept")
+	lookup.generation++
+	if result := splitter.Split("Brandschutzkonzept"); len(result) != 1 {
+		t.Errorf("Split(%q) after removing konzept = %v, want unsplit", "Brandschutzkonzept", result)
+	}
+}
+
+func TestCompoundSplitter_WithConfig(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"brand": true, "schutz": true, "konzept": true, "damm": true}}
+	splitter := NewCompoundSplitterWithConfig(lookup, SplitterConfig{
+		CacheSize:   10,
+		MaxSegments: 2,
+		Suffixes:    []string{"ung"},
+	})
+
+	if !splitter.CacheEnabled() || splitter.CacheSize() != 0 {
+		t.Errorf("Expected empty cache to be enabled, got enabled=%v size=%d", splitter.CacheEnabled(), splitter.CacheSize())
+	}
+
+	// Three segments exceed MaxSegments
+	if result := splitter.Split("Brandschutzkonzept"); len(result) != 1 {
+		t.Errorf("Split(%q) = %v, want unsplit", "Brandschutzkonzept", result)
+	}
+	if result := splitter.Split("Brandschutz"); strings.Join(result, "|") != "brand|schutz" {
+		t.Errorf("Split(%q) = %v, want [brand schutz]", "Brandschutz", result)
+	}
+
+	// Only the configured suffixes are stripped
+	if result := splitter.Split("Branddämmung"); strings.Join(result, "|") != "brand|dämmung" {
+		t.Errorf("Split(%q) = %v, want [brand dämmung]", "Branddämmung", result)
+	}
+	if result := splitter.Split("Brandkonzepte"); len(result) != 1 {
+		t.Errorf("Split(%q) = %v, want unsplit without the 'e' suffix", "Brandkonzepte", result)
+	}
+
+	cfg := splitter.Config()
+	if cfg.CacheSize != 10 || cfg.MaxWordLength != DefaultMaxWordLength || cfg.MaxSegments != 2 {
+		t.Errorf("Config() = %+v, want the constructor settings with default MaxWordLength", cfg)
+	}
+}
+
+func TestCompoundSplitter_ZeroConfig(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"brand": true, "konzept": true}}
+	splitter := NewCompoundSplitterWithConfig(lookup, SplitterConfig{})
+
+	if splitter.CacheEnabled() {
+		t.Error("Expected zero config to disable the cache")
+	}
+	if result := splitter.Split("Brandkonzepte"); strings.Join(result, "|") != "brand|konzepte" {
+		t.Errorf("Split(%q) = %v, want [brand konzepte]", "Brandkonzepte", result)
+	}
+}
+
+func TestCompoundSplitter_SplitRecursive(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{
+		"wärmedämmung": true, "wärme": true, "dämmung": true,
+		"brandschutz": true, "brand": true, "schutz": true,
+		"konzept": true, "haus": true,
+		"lieferbarkeit": true, "liefer": true, "bark": true,
+		"parteitag": true, "part": true, "ei": true, "tag": true,
+		"tausende": true, "tau": true, "sende": true,
+		"regression": true, "regress": true, "ion": true,
+	}}
+	splitter := NewCompoundSplitter(lookup)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// Dictionary entry that is itself a compound
+		{"Wärmedämmung", "wärme|dämmung"},
+		// Top-level segment re-split
+		{"Brandschutzkonzept", "brand|schutz|konzept"},
+		// Atomic word: recursion terminates
+		{"Haus", "haus"},
+		// Unknown word stays whole
+		{"Xyzabc", "xyzabc"},
+		// Dictionary words are not re-split into short or suffix-stripped parts
+		{"Lieferbarkeit", "lieferbarkeit"},
+		{"Parteitags", "parteitags"},
+		{"Tausende", "tausende"},
+		{"Regression", "regression"},
+	}
+
+	for _, tt := range tests {
+		result := strings.Join(splitter.SplitRecursive(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("SplitRecursive(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Split itself stays flat
+	if result := strings.Join(splitter.Split("Brandschutzkonzept"), "|"); result != "brandschutz|konzept" {
+		t.Errorf("Split(%q) = %q, want %q", "Brandschutzkonzept", result, "brandschutz|konzept")
+	}
+
+	// An unknown word costs one dictionary check beyond Split, not a
+	// second decomposition attempt
+	splitCounter := &countingLookup{setLookup: *lookup}
+	recursiveCounter := &countingLookup{setLookup: *lookup}
+	word := "Brandxyzschutzabc"
+	if result := NewCompoundSplitterWithConfig(splitCounter, SplitterConfig{}).Split(word); len(result) != 1 {
+		t.Fatalf("Split(%q) = %v, want it unsplit", word, result)
+	}
+	result := NewCompoundSplitterWithConfig(recursiveCounter, SplitterConfig{}).SplitRecursive(word)
+	if strings.Join(result, "|") != "brandxyzschutzabc" {
+		t.Errorf("SplitRecursive(%q) = %v, want [brandxyzschutzabc]", word, result)
+	}
+	if recursiveCounter.calls != splitCounter.calls+1 {
+		t.Errorf("SplitRecursive(%q) made %d lookups, want %d", word, recursiveCounter.calls, splitCounter.calls+1)
+	}
+}
+
+func TestCompoundSplitter_MinSegmentLength(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"öl": true, "heizung": true, "a": true, "klasse": true}}
+
+	tests := []struct {
+		name     string
+		cfg      SplitterConfig
+		input    string
+		expected string
+	}{
+		{"default admits two-rune öl", SplitterConfig{}, "Ölheizung", "öl|heizung"},
+		{"raised prefix minimum rejects öl", SplitterConfig{MinPrefixLength: 3}, "Ölheizung", "ölheizung"},
+		{"raised segment minimum rejects öl", SplitterConfig{MinSegmentLength: 3}, "Ölheizung", "ölheizung"},
+		{"default rejects single rune", SplitterConfig{}, "Aklasse", "aklasse"},
+		{"lowered prefix minimum alone is not enough", SplitterConfig{MinPrefixLength: 1}, "Aklasse", "aklasse"},
+		{"both lowered admit single rune", SplitterConfig{MinPrefixLength: 1, MinSegmentLength: 1}, "Aklasse", "a|klasse"},
+	}
+
+	for _, tt := range tests {
+		splitter := NewCompoundSplitterWithConfig(lookup, tt.cfg)
+		result := strings.Join(splitter.Split(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("%s: Split(%q) = %q, want %q", tt.name, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCompoundSplitter_TieBreak(t *testing.T) {
+	// Two segmentations with the same segment count
+	lookup := &setLookup{words: map[string]bool{"wachs": true, "tube": true, "wach": true, "stube": true}}
+	greedy := []string{"wachs", "tube"}
+	other := []string{"wach", "stube"}
+
+	for _, splitter := range []*CompoundSplitter{
+		NewCompoundSplitter(lookup),
+		NewCompoundSplitterNoCache(lookup),
+	} {
+		for i := 0; i < 3; i++ {
+			result := splitter.Split("Wachstube")
+			if strings.Join(result, "|") != "wachs|tube" {
+				t.Errorf("Split(%q) = %v, want %v", "Wachstube", result, greedy)
+			}
+		}
+	}
+
+	if !segmentationLess(greedy, other) || segmentationLess(other, greedy) {
+		t.Errorf("Expected %v to order before %v", greedy, other)
+	}
+}
+
+func TestCompoundSplitter_Frequencies(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{
+		"wachs": true, "tube": true, "wach": true, "stube": true,
+		"brandschutz": true, "brand": true, "schutz": true, "konzept": true,
+	}}
+
+	tests := []struct {
+		frequencies map[string]int
+		input       string
+		expected    string
+	}{
+		// Same segment count: the more frequent first differing segment wins
+		{map[string]int{"wach": 10, "wachs": 1}, "Wachstube", "wach|stube"},
+		{map[string]int{"wach": 1, "wachs": 10}, "Wachstube", "wachs|tube"},
+		// Equal frequencies: lexicographically smaller
+		{map[string]int{}, "Wachstube", "wach|stube"},
+		// Without frequencies the greedy split stands
+		{nil, "Wachstube", "wachs|tube"},
+		// Segmentations with more segments are not considered
+		{map[string]int{"brand": 100, "schutz": 100}, "Brandschutzkonzept", "brandschutz|konzept"},
+	}
+
+	for _, tt := range tests {
+		splitter := NewCompoundSplitterWithConfig(lookup, SplitterConfig{Frequencies: tt.frequencies})
+		result := strings.Join(splitter.Split(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("Split(%q) with Frequencies %v = %q, want %q", tt.input, tt.frequencies, result, tt.expected)
+		}
+	}
+}
+
+func TestFrequencyLess(t *testing.T) {
+	frequencies := map[string]int{"wach": 2, "wachs": 1}
+	tests := []struct {
+		a, b     []string
+		expected bool
+	}{
+		{[]string{"wach", "stube"}, []string{"wachs", "tube"}, true},
+		{[]string{"wachs", "tube"}, []string{"wach", "stube"}, false},
+		// Unranked words tie and fall back to lexicographic order
+		{[]string{"ab", "cd"}, []string{"ac", "bd"}, true},
+		{[]string{"wach", "stube"}, []string{"wach", "stube"}, false},
+	}
+
+	for _, tt := range tests {
+		result := frequencyLess(frequencies, tt.a, tt.b)
+		if result != tt.expected {
+			t.Errorf("frequencyLess(%v, %v) = %v, want %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestSegmentationLess(t *testing.T) {
+	tests := []struct {
+		a, b     []string
+		expected bool
+	}{
+		// Longer first differing segment, even with more segments
+		{[]string{"brandschutz"}, []string{"brand", "schutz"}, true},
+		{[]string{"abcd", "ef", "gh"}, []string{"ab", "cdefgh"}, true},
+		{[]string{"wachs", "tube"}, []string{"wach", "stube"}, true},
+		// Equal lengths: lexicographic
+		{[]string{"ab", "cd"}, []string{"ac", "bd"}, true},
+		{[]string{"ac", "bd"}, []string{"ab", "cd"}, false},
+		// Equal segmentations
+		{[]string{"brand", "schutz"}, []string{"brand", "schutz"}, false},
+	}
+
+	for _, tt := range tests {
+		result := segmentationLess(tt.a, tt.b)
+		if result != tt.expected {
+			t.Errorf("segmentationLess(%v, %v) = %v, want %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+// countingLookup is a setLookup that counts Contains calls.
+type countingLookup struct {
+	setLookup
+	calls int
+}
+
+func (c *countingLookup) Contains(word string) bool {
+	c.calls++
+	return c.setLookup.Contains(word)
+}
+
+func TestCompoundSplitter_MaxLookups(t *testing.T) {
+	// Only "ab" is a word, so each position of "abab…" tries every prefix
+	// length before the two-rune match: quadratic in the word length.
+	word := strings.Repeat("ab", 150)
+	newLookup := func() *countingLookup {
+		return &countingLookup{setLookup: setLookup{words: map[string]bool{"ab": true}}}
+	}
+
+	unbounded := newLookup()
+	splitter := NewCompoundSplitterWithConfig(unbounded, SplitterConfig{MaxWordLength: len(word)})
+	if result := splitter.Split(word); len(result) != 150 {
+		t.Fatalf("Split without MaxLookups = %d segments, want 150", len(result))
+	}
+
+	bounded := newLookup()
+	splitter = NewCompoundSplitterWithConfig(bounded, SplitterConfig{MaxWordLength: len(word), MaxLookups: 500})
+	result := splitter.Split(word)
+	if len(result) != 1 || result[0] != word {
+		t.Errorf("Split with MaxLookups=500 = %d segments, want unsplit", len(result))
+	}
+	if bounded.calls >= unbounded.calls/10 {
+		t.Errorf("Split with MaxLookups=500 made %d lookups, unbounded made %d", bounded.calls, unbounded.calls)
+	}
+
+	// Words within the budget still split
+	if result := splitter.Split("ababab"); len(result) != 3 {
+		t.Errorf("Split(%q) with MaxLookups=500 = %v, want 3 segments", "ababab", result)
+	}
+
+	if _, reason := splitter.SplitExplain(word); !strings.Contains(reason, "lookup limit") {
+		t.Errorf("SplitExplain reason = %q, want lookup limit", reason)
+	}
+}
+
+func TestCompoundSplitter_AllSplits(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{
+		"wachs": true, "tube": true, "wach": true, "stube": true,
+		"staub": true, "ecken": true, "stau": true, "becken": true, "staubecken": true,
+	}}
+	splitter := NewCompoundSplitterNoCache(lookup)
+
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		{"Wachstube", [][]string{{"wachs", "tube"}, {"wach", "stube"}}},
+		// The whole word is listed when it is in the dictionary
+		{"Staubecken", [][]string{{"staubecken"}, {"staub", "ecken"}, {"stau", "becken"}}},
+		{"Xyz", nil},
+	}
+
+	for _, tt := range tests {
+		result := splitter.AllSplits(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("AllSplits(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+		// Split picks the first segmentation
+		if len(result) > 0 && !reflect.DeepEqual(splitter.Split(tt.input), result[0]) {
+			t.Errorf("Split(%q) = %v, want AllSplits()[0] = %v", tt.input, splitter.Split(tt.input), result[0])
+		}
+	}
+}
+
+func TestCompoundSplitter_AllSplitsCap(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"aa": true, "aaa": true, "aaaa": true}}
+	word := strings.Repeat("a", 16)
+
+	all := NewCompoundSplitterNoCache(lookup).AllSplits(word)
+	if len(all) <= 5 {
+		t.Fatalf("AllSplits(%q) = %d segmentations, want more than 5", word, len(all))
+	}
+	for i, segments := range all {
+		if strings.Join(segments, "") != word {
+			t.Errorf("AllSplits(%q)[%d] = %v does not reconstruct the word", word, i, segments)
+		}
+		if i > 0 && !segmentationLess(all[i-1], segments) {
+			t.Errorf("AllSplits(%q) out of order at %d: %v before %v", word, i, all[i-1], segments)
+		}
+	}
+
+	capped := NewCompoundSplitterWithConfig(lookup, SplitterConfig{MaxAllSplits: 5}).AllSplits(word)
+	if !reflect.DeepEqual(capped, all[:5]) {
+		t.Errorf("AllSplits(%q) with MaxAllSplits=5 = %v, want %v", word, capped, all[:5])
+	}
+
+	// MaxSegments applies as in Split
+	for _, segments := range NewCompoundSplitterWithConfig(lookup, SplitterConfig{MaxSegments: 4}).AllSplits(word) {
+		if len(segments) > 4 {
+			t.Errorf("AllSplits(%q) with MaxSegments=4 returned %v", word, segments)
+		}
+	}
+}
+
+func TestCompoundSplitter_ExportImportCache(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"brand": true, "schutz": true, "konzept": true, "haus": true}}
+	splitter := NewCompoundSplitter(lookup)
+
+	words := []string{"Brandschutz", "Hauskonzept", "Xyz", "Haus"}
+	for _, word := range words {
+		splitter.Split(word)
+	}
+
+	var buf bytes.Buffer
+	if err := splitter.ExportCache(&buf); err != nil {
+		t.Fatalf("ExportCache() error: %v", err)
+	}
+	expected := "brandschutz\tbrand\tschutz\nhauskonzept\thaus\tkonzept\nxyz\txyz\nhaus\thaus\n"
+	if buf.String() != expected {
+		t.Errorf("ExportCache() = %q, want %q", buf.String(), expected)
+	}
+
+	// A fresh splitter serves the imported splits from its cache
+	warm := NewCompoundSplitter(lookup)
+	if err := warm.ImportCache(strings.NewReader(buf.String())); err != nil {
+		t.Fatalf("ImportCache() error: %v", err)
+	}
+	if warm.CacheSize() != len(words) {
+		t.Errorf("CacheSize() after import = %d, want %d", warm.CacheSize(), len(words))
+	}
+	for _, word := range words {
+		if result, expected := warm.Split(word), splitter.Split(word); !reflect.DeepEqual(result, expected) {
+			t.Errorf("Split(%q) after import = %v, want %v", word, result, expected)
+		}
+	}
+	if stats := warm.CacheStats(); stats.Misses != 0 || stats.Hits != len(words) {
+		t.Errorf("CacheStats() after import = %+v, want %d hits and no misses", stats, len(words))
+	}
+
+	// Exporting again reproduces the same entries
+	var again bytes.Buffer
+	if err := warm.ExportCache(&again); err != nil {
+		t.Fatalf("ExportCache() error: %v", err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("Round-tripped ExportCache() = %q, want %q", again.String(), buf.String())
+	}
+}
+
+func TestCompoundSplitter_ImportCacheErrors(t *testing.T) {
+	lookup := &setLookup{words: map[string]bool{"brand": true}}
+
+	if err := NewCompoundSplitterNoCache(lookup).ImportCache(strings.NewReader("haus\thaus\n")); !errors.Is(err, ErrCacheDisabled) {
+		t.Errorf("ImportCache() without cache error = %v, want ErrCacheDisabled", err)
+	}
+
+	for _, input := range []string{"brandschutz\tbrand\tschutze\n", "haus\n"} {
+		if err := NewCompoundSplitter(lookup).ImportCache(strings.NewReader(input)); err == nil {
+			t.Errorf("ImportCache(%q): expected error", input)
+		}
+	}
+
+	// Stale entries are not exported
+	splitter := NewCompoundSplitter(lookup)
+	splitter.Split("brandschutz")
+	lookup.generation++
+	var buf bytes.Buffer
+	if err := splitter.ExportCache(&buf); err != nil {
+		t.Fatalf("ExportCache() error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("ExportCache() after dictionary change = %q, want empty", buf.String())
+	}
+}
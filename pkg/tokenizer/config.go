@@ -0,0 +1,189 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// stepPositionNames maps StepPosition values to their JSON names.
+var stepPositionNames = map[StepPosition]string{
+	CustomBeforeStem: "before_stem",
+	CustomFirst:      "first",
+	CustomLast:       "last",
+}
+
+// normalizationFormNames maps NormalizationForm values to their JSON names.
+var normalizationFormNames = map[NormalizationForm]string{
+	FormNone: "none",
+	FormNFC:  "nfc",
+	FormNFD:  "nfd",
+	FormNFKC: "nfkc",
+	FormNFKD: "nfkd",
+}
+
+// outputCaseNames maps OutputCase values to their JSON names.
+var outputCaseNames = map[OutputCase]string{
+	OutputLower:    "lower",
+	OutputOriginal: "original",
+	OutputTitle:    "title",
+}
+
+// segmentNormalizationNames maps SegmentNormalization values to their JSON names.
+var segmentNormalizationNames = map[SegmentNormalization]string{
+	SegmentFull:          "full",
+	SegmentLowercaseOnly: "lowercase",
+	SegmentNone:          "none",
+}
+
+// LoadConfig reads a JSON-encoded Config from r and validates it.
+// Unknown fields are rejected so typos don't silently fall back to false.
+func LoadConfig(r io.Reader) (Config, error) {
+	var cfg Config
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decode config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the config for invalid values.
+func (c Config) Validate() error {
+	if c.CacheSize < 0 {
+		return fmt.Errorf("invalid cache_size %d: must be >= 0", c.CacheSize)
+	}
+
+	if c.MaxWordLength < 0 {
+		return fmt.Errorf("invalid max_word_length %d: must be >= 0", c.MaxWordLength)
+	}
+
+	if c.MaxSegments < 0 {
+		return fmt.Errorf("invalid max_segments %d: must be >= 0", c.MaxSegments)
+	}
+	if c.MaxLookups < 0 {
+		return fmt.Errorf("invalid max_lookups %d: must be >= 0", c.MaxLookups)
+	}
+	if c.SplitThreshold < 0 || c.SplitThreshold > 1 {
+		return fmt.Errorf("invalid split_threshold %g: must be between 0 and 1", c.SplitThreshold)
+	}
+	if c.NgramMin < 0 || c.NgramMax < 0 {
+		return fmt.Errorf("invalid ngram size %d..%d: must be >= 0", c.NgramMin, c.NgramMax)
+	}
+	if minN, maxN := orDefault(c.NgramMin, DefaultNgramMin), orDefault(c.NgramMax, DefaultNgramMax); minN > maxN {
+		return fmt.Errorf("invalid ngram size %d..%d: min exceeds max", minN, maxN)
+	}
+
+	if c.BatchWorkers < 0 {
+		return fmt.Errorf("invalid batch_workers %d: must be >= 0", c.BatchWorkers)
+	}
+	if _, ok := outputCaseNames[c.OutputCase]; !ok {
+		return fmt.Errorf("invalid output_case %d", c.OutputCase)
+	}
+	if _, ok := segmentNormalizationNames[c.SegmentNormalization]; !ok {
+		return fmt.Errorf("invalid segment_normalization %d", c.SegmentNormalization)
+	}
+
+	nc := c.Normalizers
+	if nc.MinStemLength < 0 {
+		return fmt.Errorf("invalid min_stem_length %d: must be >= 0", nc.MinStemLength)
+	}
+	if _, ok := normalizationFormNames[nc.NormalizationForm]; !ok {
+		return fmt.Errorf("invalid normalization_form %d", nc.NormalizationForm)
+	}
+	if nc.NFKDDecompose && nc.NormalizationForm != FormNone {
+		return fmt.Errorf("nfkd_decompose and normalization_form %q are mutually exclusive", normalizationFormNames[nc.NormalizationForm])
+	}
+	if _, ok := nc.Confusables[""]; ok {
+		return fmt.Errorf("invalid confusables: empty key")
+	}
+	if _, ok := stepPositionNames[nc.CustomPosition]; !ok {
+		return fmt.Errorf("invalid custom_position %d", nc.CustomPosition)
+	}
+	return nil
+}
+
+// MarshalText encodes the position as "before_stem", "first" or "last".
+func (p StepPosition) MarshalText() ([]byte, error) {
+	name, ok := stepPositionNames[p]
+	if !ok {
+		return nil, fmt.Errorf("invalid step position %d", p)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a position name produced by MarshalText.
+func (p *StepPosition) UnmarshalText(text []byte) error {
+	for pos, name := range stepPositionNames {
+		if name == string(text) {
+			*p = pos
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown step position %q", text)
+}
+
+// MarshalText encodes the form as "none", "nfc", "nfd", "nfkc" or "nfkd".
+func (f NormalizationForm) MarshalText() ([]byte, error) {
+	name, ok := normalizationFormNames[f]
+	if !ok {
+		return nil, fmt.Errorf("invalid normalization form %d", f)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a form name produced by MarshalText.
+func (f *NormalizationForm) UnmarshalText(text []byte) error {
+	for form, name := range normalizationFormNames {
+		if name == string(text) {
+			*f = form
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown normalization form %q", text)
+}
+
+// MarshalText encodes the casing as "lower", "original" or "title".
+func (o OutputCase) MarshalText() ([]byte, error) {
+	name, ok := outputCaseNames[o]
+	if !ok {
+		return nil, fmt.Errorf("invalid output case %d", o)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a casing name produced by MarshalText.
+func (o *OutputCase) UnmarshalText(text []byte) error {
+	for mode, name := range outputCaseNames {
+		if name == string(text) {
+			*o = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output case %q", text)
+}
+
+// MarshalText encodes the mode as "full", "lowercase" or "none".
+func (m SegmentNormalization) MarshalText() ([]byte, error) {
+	name, ok := segmentNormalizationNames[m]
+	if !ok {
+		return nil, fmt.Errorf("invalid segment normalization %d", m)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a mode name produced by MarshalText.
+func (m *SegmentNormalization) UnmarshalText(text []byte) error {
+	for mode, name := range segmentNormalizationNames {
+		if name == string(text) {
+			*m = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown segment normalization %q", text)
+}
@@ -0,0 +1,80 @@
+package tokenizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_RoundTrip(t *testing.T) {
+	cfg := testConfig()
+	cfg.EmitVariants = true
+	cfg.Normalizers.MinStemLength = 4
+	cfg.Normalizers.CustomPosition = CustomLast
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	loaded, err := LoadConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("LoadConfig() = %+v, want %+v", loaded, cfg)
+	}
+}
+
+func TestLoadConfig_NewTokenizer(t *testing.T) {
+	input := `{
+		"cache": true,
+		"lowercase_original": false,
+		"normalizers": {
+			"nfkd_decompose": true,
+			"lowercase": true,
+			"convert_eszett": true,
+			"remove_combining_marks": true,
+			"custom_position": "before_stem"
+		}
+	}`
+
+	cfg, err := LoadConfig(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	tok, err := NewTokenizer(getTestDictPath(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Größe")
+	if len(result) != 1 || result[0] != "grosse" {
+		t.Errorf("Tokenize(%q) = %v, want [grosse]", "Größe", result)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []string{
+		`{"cache": true, "unknown_field": true}`,
+		`{"cache_size": -1}`,
+		`{"split_threshold": 1.5}`,
+		`{"normalizers": {"min_stem_length": -1}}`,
+		`{"normalizers": {"custom_position": "middle"}}`,
+		`{"normalizers": {"confusables": {"": "m"}}}`,
+		`{"normalizers": {"normalization_form": "nfx"}}`,
+		`{"normalizers": {"nfkd_decompose": true, "normalization_form": "nfc"}}`,
+		`not json`,
+	}
+
+	for _, input := range tests {
+		if _, err := LoadConfig(strings.NewReader(input)); err == nil {
+			t.Errorf("LoadConfig(%q) expected error", input)
+		}
+	}
+}
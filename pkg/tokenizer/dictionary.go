@@ -2,32 +2,88 @@ package tokenizer
 
 import (
 	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/blevesearch/vellum"
 )
 
 // Dictionary holds German compound word components in an FST for fast lookups.
+// It is safe for concurrent use.
 type Dictionary struct {
-	fst     *vellum.FST
-	words   map[string]struct{} // Source of truth for modifications
-	fstPath string
-	txtPath string
-	mu      sync.RWMutex
+	fst        *vellum.FST
+	words      map[string]struct{} // Source of truth for modifications
+	fstPath    string
+	txtPath    string
+	mu         sync.RWMutex
+	generation atomic.Uint64 // Bumped on every FST rebuild
+	txtStat    fileStamp     // Text file state as last read or written
+	fstVersion int           // Header format version of the loaded FST
+	dirty      bool          // Words changed since the FST and text file were last written
+	closed     bool
+	logger     *slog.Logger
+	inMemory   bool // Read the FST into memory instead of memory-mapping it
+
+	// Optional prefilter rejecting most non-words before the FST lookup
+	bloom     *bloomFilter
+	bloomRate float64
+
+	// Optional append-only log of AddWord/RemoveWord changes (WithJournal)
+	journalPath    string
+	journalFile    *os.File
+	journalEntries int // Changes in the journal not yet merged into the text file
+	compactAfter   int // Journal size that triggers compaction, 0 = never
+}
+
+// ErrDictionaryClosed is returned when reloading a dictionary after Close.
+var ErrDictionaryClosed = errors.New("dictionary is closed")
+
+// DefaultWatchInterval is how often WatchFile polls the text file for changes.
+const DefaultWatchInterval = time.Second
+
+// fileStamp identifies a version of a file by size and modification time.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// statFile returns the current stamp of path.
+func statFile(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime()}, nil
 }
 
 // NewDictionary loads the German compound word components dictionary from file into an FST.
-// If FST doesn't exist, builds it from the text file.
-func NewDictionary(txtPath string) (*Dictionary, error) {
-	fstPath := strings.TrimSuffix(txtPath, ".txt") + ".fst"
+// If FST doesn't exist, builds it from the text file. A text file ending in
+// ".gz" is read and written gzip-compressed; its FST is cached uncompressed
+// ("words.txt.gz" → "words.fst").
+func NewDictionary(txtPath string, opts ...DictionaryOption) (*Dictionary, error) {
+	fstPath := fstPathFor(txtPath)
 
 	d := &Dictionary{
 		words:   make(map[string]struct{}, 35000),
 		fstPath: fstPath,
 		txtPath: txtPath,
+		logger:  nopLogger,
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
 
 	if err := d.loadTextFile(); err != nil {
@@ -38,69 +94,362 @@ func NewDictionary(txtPath string) (*Dictionary, error) {
 		return nil, err
 	}
 
+	// Replayed after the FST is loaded, so the FST of the text file is reused
+	// and journaled words are served from the word set until compaction
+	if d.journalPath != "" {
+		if err := d.replayJournal(); err != nil {
+			d.fst.Close()
+			return nil, err
+		}
+	}
+
 	return d, nil
 }
 
-// loadTextFile reads words from the source text file.
+// NewDictionaryInMemoryFST is like NewDictionary but reads the FST file
+// into memory instead of memory-mapping it, see WithInMemoryFST.
+func NewDictionaryInMemoryFST(txtPath string, opts ...DictionaryOption) (*Dictionary, error) {
+	return NewDictionary(txtPath, append(opts, WithInMemoryFST())...)
+}
+
+// WithInMemoryFST reads the FST file fully into memory on load and rebuild
+// instead of memory-mapping it, so lookups no longer depend on the file
+// once it is loaded, e.g. in containers with ephemeral storage. The FST is
+// still written to disk as a cache for the next load.
+func WithInMemoryFST() DictionaryOption {
+	return func(d *Dictionary) {
+		d.inMemory = true
+	}
+}
+
+// openFST opens the FST file, memory-mapped unless WithInMemoryFST is set.
+func (d *Dictionary) openFST() (*vellum.FST, error) {
+	return d.openFSTAt(d.fstPath)
+}
+
+// openFSTAt opens the FST file at path like openFST.
+func (d *Dictionary) openFSTAt(path string) (*vellum.FST, error) {
+	if !d.inMemory {
+		return vellum.Open(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return vellum.Load(data)
+}
+
+// fstPathFor returns the FST path belonging to a dictionary text file.
+// Both "words.txt" and "words.txt.gz" map to "words.fst".
+func fstPathFor(txtPath string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(txtPath, gzipExt), ".txt") + ".fst"
+}
+
+// gzipExt marks word list files that are gzip-compressed.
+const gzipExt = ".gz"
+
+// isGzipPath reports whether path names a gzip-compressed word list.
+func isGzipPath(path string) bool {
+	return strings.HasSuffix(path, gzipExt)
+}
+
+// loadTextFile reads words from the source text file, replacing the current word set.
 func (d *Dictionary) loadTextFile() error {
-	file, err := os.Open(d.txtPath)
+	words, stamp, err := d.readTextFile()
+	if err != nil {
+		return err
+	}
+	d.words = words
+	d.txtStat = stamp
+	return nil
+}
+
+// readTextFile reads the word set from the source text file and returns it
+// with the file's stamp, leaving the current word set alone.
+func (d *Dictionary) readTextFile() (map[string]struct{}, fileStamp, error) {
+	stamp, err := statFile(d.txtPath)
+	if err != nil {
+		return nil, fileStamp{}, err
+	}
+
+	words := make(map[string]struct{}, len(d.words))
+	err = readWordFile(d.txtPath, func(word string) {
+		words[strings.ToLower(word)] = struct{}{}
+	})
+	if err != nil {
+		return nil, fileStamp{}, err
+	}
+	return words, stamp, nil
+}
+
+// readWordFile calls fn with each entry of a word list file: one entry per
+// line, surrounding whitespace trimmed, blank lines and # comments skipped.
+// Files ending in ".gz" are decompressed.
+func readWordFile(path string, fn func(word string)) error {
+	file, err := os.Open(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	var r io.Reader = file
+	if isGzipPath(path) {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		word := strings.TrimSpace(scanner.Text())
 		if word == "" || strings.HasPrefix(word, "#") {
 			continue
 		}
-		d.words[strings.ToLower(word)] = struct{}{}
+		fn(word)
 	}
 	return scanner.Err()
 }
 
+// writeWordFile writes words to path, one per line, gzip-compressed if path
+// ends in ".gz".
+func writeWordFile(path string, words []string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var gz *gzip.Writer
+	var dst io.Writer = file
+	if isGzipPath(path) {
+		gz = gzip.NewWriter(file)
+		dst = gz
+	}
+
+	w := bufio.NewWriter(dst)
+	for _, word := range words {
+		w.WriteString(word)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
 // loadOrBuildFST loads existing FST or builds a new one.
+// The existing FST is only used if its header matches the current format
+// version, word count and text file size/mtime, and its checksum is intact.
+// An unchanged text file therefore skips the rebuild on startup.
+//
+// An FST that passes those checks but was written by an incompatible vellum
+// version may still open and answer wrongly, so it is also probed against
+// the word set before use.
 func (d *Dictionary) loadOrBuildFST() error {
-	if fst, err := vellum.Open(d.fstPath); err == nil {
-		d.fst = fst
-		return nil
+	if d.fstIsCurrent() {
+		fst, err := d.openFST()
+		if err == nil {
+			if err = d.checkFST(fst); err == nil {
+				d.fst = fst
+				d.fstVersion = FSTFormatVersion
+				return nil
+			}
+			fst.Close()
+		}
+		d.logger.Warn("FST rejected, rebuilding", "path", d.fstPath, "error", err)
 	}
 
 	return d.rebuildFST()
 }
 
+// fstProbeWords is how many words checkFST looks up in a loaded FST.
+const fstProbeWords = 16
+
+// checkFST verifies that fst agrees with the word set: same word count,
+// its smallest and largest keys are known words, and a sample of words
+// from the set is found.
+func (d *Dictionary) checkFST(fst *vellum.FST) error {
+	if fst.Len() != len(d.words) {
+		return fmt.Errorf("FST has %d words, text file has %d", fst.Len(), len(d.words))
+	}
+	if len(d.words) == 0 {
+		return nil
+	}
+
+	for _, key := range [][]byte{keyOrNil(fst.GetMinKey()), keyOrNil(fst.GetMaxKey())} {
+		if _, ok := d.words[string(key)]; !ok {
+			return fmt.Errorf("FST key %q is not in the text file", key)
+		}
+	}
+
+	probes := 0
+	for word := range d.words {
+		if probes == fstProbeWords {
+			break
+		}
+		probes++
+		if ok, err := fst.Contains([]byte(word)); err != nil || !ok {
+			return fmt.Errorf("FST is missing %q", word)
+		}
+	}
+	return nil
+}
+
+// keyOrNil returns key, or nil if err is set so the caller's lookup fails.
+func keyOrNil(key []byte, err error) []byte {
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// fstIsCurrent reports whether the FST on disk can be reused.
+func (d *Dictionary) fstIsCurrent() bool {
+	header, err := readFSTHeader(headerPath(d.fstPath))
+	if err != nil {
+		return false
+	}
+	if header.Version != FSTFormatVersion || header.WordCount != uint64(len(d.words)) {
+		return false
+	}
+	if !header.matchesText(d.txtStat) {
+		return false
+	}
+
+	sum, err := fileChecksum(d.fstPath)
+	return err == nil && sum == header.Checksum
+}
+
 // Contains checks if a word exists in the dictionary (case-insensitive).
-// Always uses FST for lookups.
+// The FST answers lookups, except that words the bloom filter (if enabled)
+// rules out are rejected without touching the FST. While there are
+// unflushed changes, lookups are answered from the word set instead.
+// Nothing is found once the dictionary is closed.
 func (d *Dictionary) Contains(word string) bool {
 	lower := strings.ToLower(word)
 
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
+	if d.closed {
+		return false
+	}
+	if d.bloom != nil && !d.bloom.mayContain(lower) {
+		return false
+	}
+	if d.dirty {
+		_, exists := d.words[lower]
+		return exists
+	}
 	_, exists, _ := d.fst.Get([]byte(lower))
 	return exists
 }
 
-// AddWord adds a word to the dictionary and rebuilds FST.
+// EnableBloomFilter builds a bloom filter over the word set that Contains
+// consults before the FST, so most non-words are rejected in O(1). The
+// FST still confirms every positive, so results are unchanged. A rate of 0
+// uses DefaultBloomFalsePositiveRate. The filter is rebuilt on every change
+// to the word set.
+func (d *Dictionary) EnableBloomFilter(falsePositiveRate float64) error {
+	if falsePositiveRate == 0 {
+		falsePositiveRate = DefaultBloomFalsePositiveRate
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return fmt.Errorf("invalid bloom false positive rate %v: must be in (0, 1)", falsePositiveRate)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.bloomRate = falsePositiveRate
+	d.rebuildBloom()
+	return nil
+}
+
+// rebuildBloom rebuilds the bloom filter if enabled (caller must hold lock).
+func (d *Dictionary) rebuildBloom() {
+	if d.bloomRate == 0 {
+		return
+	}
+	bloom := newBloomFilter(len(d.words), d.bloomRate)
+	for word := range d.words {
+		bloom.add(word)
+	}
+	d.bloom = bloom
+}
+
+// AddWord adds a word to the dictionary. The change is visible to lookups
+// immediately; the FST rebuild and text file write are deferred until Flush,
+// RebuildFST or Close, so a batch of mutations costs a single rebuild.
+// With WithJournal, the word is appended to the journal instead.
 func (d *Dictionary) AddWord(word string) error {
 	lower := strings.ToLower(word)
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if err := d.journalChange(journalAdd, lower); err != nil {
+		return err
+	}
 	d.words[lower] = struct{}{}
-	return d.rebuildFST()
+	if d.bloom != nil {
+		d.bloom.add(lower)
+	}
+	d.markDirty()
+	return d.compactIfDue()
 }
 
-// RemoveWord removes a word from the dictionary and rebuilds FST.
+// RemoveWord removes a word from the dictionary. Like AddWord, the FST
+// rebuild is deferred until Flush, RebuildFST or Close, or the removal is
+// journaled with WithJournal.
 func (d *Dictionary) RemoveWord(word string) error {
 	lower := strings.ToLower(word)
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if err := d.journalChange(journalRemove, lower); err != nil {
+		return err
+	}
 	delete(d.words, lower)
+	d.markDirty()
+	return d.compactIfDue()
+}
+
+// markDirty records an unflushed change (caller must hold lock). The
+// generation bump invalidates cached splits right away.
+func (d *Dictionary) markDirty() {
+	d.dirty = true
+	d.generation.Add(1)
+}
+
+// IsDirty reports whether the word set has changes not yet written to the
+// FST and text file.
+func (d *Dictionary) IsDirty() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.dirty
+}
+
+// Flush rebuilds the FST and saves the text file if there are unflushed
+// changes. It is a no-op otherwise.
+func (d *Dictionary) Flush() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.dirty {
+		return nil
+	}
 	return d.rebuildFST()
 }
 
@@ -111,87 +460,462 @@ func (d *Dictionary) RebuildFST() error {
 	return d.rebuildFST()
 }
 
-// rebuildFST rebuilds FST without locking (caller must hold lock).
+// rebuildFST rebuilds FST without locking (caller must hold lock), then
+// saves the word set to the text file and clears the journal.
 func (d *Dictionary) rebuildFST() error {
-	if d.fst != nil {
-		d.fst.Close()
-		d.fst = nil
+	sortedWords := d.sortedWords()
+	if err := d.buildFST(sortedWords); err != nil {
+		return err
 	}
+	d.rebuildBloom()
 
-	sortedWords := make([]string, 0, len(d.words))
-	for word := range d.words {
-		sortedWords = append(sortedWords, word)
+	if err := d.saveTextFile(sortedWords); err != nil {
+		return err
 	}
-	sort.Strings(sortedWords)
+	d.dirty = false
+	if err := d.clearJournal(); err != nil {
+		return err
+	}
+
+	// Written last so it records the text file stamp from saveTextFile
+	return d.writeHeader(len(sortedWords))
+}
+
+// buildFST writes the FST for sortedWords and swaps it in (caller must hold
+// lock). The FST is built in a temporary file that replaces the current one
+// only once it is complete and opens, so on error the current FST stays in
+// place. The word set, bloom filter, text file, journal and header are left
+// to the caller.
+func (d *Dictionary) buildFST(sortedWords []string) error {
+	d.logger.Info("rebuilding FST", "path", d.fstPath, "words", len(sortedWords))
 
-	// Create FST file
-	fstFile, err := os.Create(d.fstPath)
+	tmpPath, err := writeFSTFile(filepath.Dir(d.fstPath), filepath.Base(d.fstPath), sortedWords)
 	if err != nil {
 		return err
 	}
+	fst, err := d.openFSTAt(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	// A memory-mapped FST stays valid when its file is renamed
+	if err := os.Rename(tmpPath, d.fstPath); err != nil {
+		fst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if d.fst != nil {
+		d.fst.Close()
+	}
+	d.fst = fst
+	d.fstVersion = FSTFormatVersion
+	d.generation.Add(1)
+	return nil
+}
+
+// writeFSTFile builds the FST for sortedWords in a new temporary file in dir
+// and returns its path. The file is removed on error.
+func writeFSTFile(dir, name string, sortedWords []string) (path string, err error) {
+	fstFile, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(fstFile.Name())
+		}
+	}()
 
 	builder, err := vellum.New(fstFile, nil)
 	if err != nil {
 		fstFile.Close()
-		return err
+		return "", err
 	}
 
+	// The builder copies each key, so one buffer serves every insert
+	var key []byte
 	for _, word := range sortedWords {
-		if err := builder.Insert([]byte(word), 0); err != nil {
+		key = append(key[:0], word...)
+		if err := builder.Insert(key, 0); err != nil {
 			builder.Close()
 			fstFile.Close()
-			return err
+			return "", err
 		}
 	}
 
 	if err := builder.Close(); err != nil {
 		fstFile.Close()
+		return "", err
+	}
+	if err := fstFile.Close(); err != nil {
+		return "", err
+	}
+	return fstFile.Name(), nil
+}
+
+// writeHeader writes the FST header for the current FST and text file.
+func (d *Dictionary) writeHeader(wordCount int) error {
+	sum, err := fileChecksum(d.fstPath)
+	if err != nil {
 		return err
 	}
-	fstFile.Close()
+	return writeFSTHeader(headerPath(d.fstPath), fstHeader{
+		Magic:       fstMagic,
+		Version:     FSTFormatVersion,
+		WordCount:   uint64(wordCount),
+		Checksum:    sum,
+		TextSize:    d.txtStat.size,
+		TextModTime: d.txtStat.modTime.UnixNano(),
+	})
+}
+
+// Reload re-reads the text file and rebuilds the FST from it, discarding
+// unflushed changes. The text file is only read, never written back, so it
+// keeps its comments and order. Journaled changes are not discarded but
+// replayed on top and kept in the journal until compaction. In-flight
+// lookups finish against the old FST before the swap. If reading the file
+// or building the FST fails, the dictionary keeps its current words and FST.
+func (d *Dictionary) Reload() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	fst, err := vellum.Open(d.fstPath)
+	if d.closed {
+		return ErrDictionaryClosed
+	}
+	words, stamp, err := d.readTextFile()
 	if err != nil {
 		return err
 	}
-	d.fst = fst
+	sortedWords := sortedKeys(words)
+	if err := d.buildFST(sortedWords); err != nil {
+		return err
+	}
+	d.words = words
+	d.txtStat = stamp
+	d.rebuildBloom()
+	d.dirty = false
+	if err := d.writeHeader(len(sortedWords)); err != nil {
+		return err
+	}
+	if d.journalPath != "" {
+		return d.replayJournal()
+	}
+	return nil
+}
 
-	return d.saveTextFile()
+// WatchFile polls the text file every DefaultWatchInterval and reloads the
+// dictionary when it changes on disk. It blocks until ctx is cancelled and
+// returns ctx.Err(), or returns nil once the dictionary is closed. A failed
+// reload, as of a file caught half-written, is logged and retried on the
+// next poll.
+// Compound splitter caches are invalidated by the generation bump on reload.
+func (d *Dictionary) WatchFile(ctx context.Context) error {
+	return d.WatchFileInterval(ctx, DefaultWatchInterval)
 }
 
-// saveTextFile writes the current word set back to the text file.
-func (d *Dictionary) saveTextFile() error {
-	sortedWords := make([]string, 0, len(d.words))
-	for word := range d.words {
-		sortedWords = append(sortedWords, word)
+// WatchFileInterval is WatchFile with a custom polling interval.
+func (d *Dictionary) WatchFileInterval(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		stamp, err := statFile(d.txtPath)
+		if err != nil {
+			// File may be briefly missing while being replaced
+			continue
+		}
+
+		d.mu.RLock()
+		closed, changed := d.closed, stamp != d.txtStat
+		d.mu.RUnlock()
+
+		if closed {
+			return nil
+		}
+		if changed {
+			d.logger.Info("dictionary text file changed, reloading", "path", d.txtPath)
+			if err := d.Reload(); errors.Is(err, ErrDictionaryClosed) {
+				return nil
+			} else if err != nil {
+				d.logger.Error("reloading dictionary failed", "path", d.txtPath, "error", err)
+			}
+		}
+	}
+}
+
+// saveTextFile writes the sorted word set back to the text file.
+func (d *Dictionary) saveTextFile(sortedWords []string) error {
+	if err := writeWordFile(d.txtPath, sortedWords); err != nil {
+		return err
 	}
-	sort.Strings(sortedWords)
 
-	file, err := os.Create(d.txtPath)
+	// Record our own write so WatchFile doesn't treat it as an external change
+	stamp, err := statFile(d.txtPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	d.txtStat = stamp
+	return nil
+}
 
-	for _, word := range sortedWords {
-		if _, err := file.WriteString(word + "\n"); err != nil {
-			return err
+// sortedWords returns the word set in sorted order (caller must hold lock).
+func (d *Dictionary) sortedWords() []string {
+	return sortedKeys(d.words)
+}
+
+// sortedKeys returns the words of a word set in sorted order.
+func sortedKeys(words map[string]struct{}) []string {
+	sorted := make([]string, 0, len(words))
+	for word := range words {
+		sorted = append(sorted, word)
+	}
+	sortStrings(sorted, runtime.GOMAXPROCS(0))
+	return sorted
+}
+
+// parallelSortThreshold is the length from which sortStrings sorts in
+// parallel; below it the goroutines cost more than they save.
+const parallelSortThreshold = 1 << 15
+
+// sortStrings sorts words in increasing order like sort.Strings. Long
+// slices are cut into parts chunks that are sorted concurrently, then
+// merged pairwise, each round of merges also running concurrently.
+func sortStrings(words []string, parts int) {
+	if parts < 2 || len(words) < parallelSortThreshold {
+		slices.Sort(words)
+		return
+	}
+
+	size := (len(words) + parts - 1) / parts
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(words); lo += size {
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			slices.Sort(chunk)
+		}(words[lo:min(lo+size, len(words))])
+	}
+	wg.Wait()
+
+	src, dst := words, make([]string, len(words))
+	for width := size; width < len(words); width *= 2 {
+		for lo := 0; lo < len(words); lo += 2 * width {
+			mid, hi := min(lo+width, len(words)), min(lo+2*width, len(words))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mergeStrings(dst[lo:hi], src[lo:mid], src[mid:hi])
+			}()
 		}
+		wg.Wait()
+		src, dst = dst, src
 	}
-	return nil
+	if &src[0] != &words[0] {
+		copy(words, src)
+	}
+}
+
+// mergeStrings merges the sorted slices a and b into dst, which must have
+// room for both.
+func mergeStrings(dst, a, b []string) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// Words returns a sorted copy of the word set.
+func (d *Dictionary) Words() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.sortedWords()
+}
+
+// AllWords returns every word in the FST in sorted order, walking the FST
+// rather than the in-memory word set. This is the authoritative view of what
+// lookups see; unflushed AddWord/RemoveWord changes are not included. It
+// returns nil once the dictionary is closed.
+func (d *Dictionary) AllWords() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return nil
+	}
+	return collectKeys(d.fst.Iterator(nil, nil))
+}
+
+// Merge adds all words from other to d and rebuilds the FST once.
+// Returns the number of words that were not already in d.
+func (d *Dictionary) Merge(other *Dictionary) (added int, err error) {
+	// Snapshot other first so the two locks are never held together
+	return d.AddWords(other.Words())
+}
+
+// AddWords adds words to the dictionary and rebuilds the FST once for the
+// whole batch. Returns the number of words that were not already present.
+func (d *Dictionary) AddWords(words []string) (added int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if _, exists := d.words[lower]; !exists {
+			d.words[lower] = struct{}{}
+			added++
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, d.rebuildFST()
+}
+
+// Import adds the words of a word list file (same format as the dictionary
+// text file) with a single FST rebuild. Returns the number of new words.
+func (d *Dictionary) Import(path string) (added int, err error) {
+	var words []string
+	if err := readWordFile(path, func(word string) { words = append(words, word) }); err != nil {
+		return 0, err
+	}
+	return d.AddWords(words)
+}
+
+// Export writes the sorted, deduplicated word set to path, one word per line.
+func (d *Dictionary) Export(path string) error {
+	return writeWordFile(path, d.Words())
+}
+
+// Diff compares the word sets of d and other and returns the sorted words
+// present only in d and only in other.
+func (d *Dictionary) Diff(other *Dictionary) (onlyA, onlyB []string) {
+	a, b := d.Words(), other.Words()
+
+	// Both lists are sorted, so a single merge pass finds the differences
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case a[i] < b[j]:
+			onlyA = append(onlyA, a[i])
+			i++
+		default:
+			onlyB = append(onlyB, b[j])
+			j++
+		}
+	}
+	onlyA = append(onlyA, a[i:]...)
+	onlyB = append(onlyB, b[j:]...)
+	return onlyA, onlyB
 }
 
-// Close releases FST resources.
+// ValidateConsistency compares the dictionary's text file and FST as they
+// are on disk and returns the words missing from each. Both are empty when
+// the files agree.
+func (d *Dictionary) ValidateConsistency() (missingFromFST, missingFromText []string, err error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return ValidateDictionaryFiles(d.txtPath)
+}
+
+// ValidateDictionaryFiles compares the text file at txtPath with the FST
+// built from it without loading a Dictionary, which would rebuild a stale
+// FST. It returns the sorted words present in only one of the two.
+func ValidateDictionaryFiles(txtPath string) (missingFromFST, missingFromText []string, err error) {
+	textWords := make(map[string]struct{})
+	err = readWordFile(txtPath, func(word string) {
+		textWords[strings.ToLower(word)] = struct{}{}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fst, err := vellum.Open(fstPathFor(txtPath))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fst.Close()
+
+	fstWords := make(map[string]struct{}, len(textWords))
+	it, err := fst.Iterator(nil, nil)
+	for err == nil {
+		key, _ := it.Current()
+		fstWords[string(key)] = struct{}{}
+		err = it.Next()
+	}
+	if !errors.Is(err, vellum.ErrIteratorDone) {
+		return nil, nil, err
+	}
+
+	for word := range textWords {
+		if _, ok := fstWords[word]; !ok {
+			missingFromFST = append(missingFromFST, word)
+		}
+	}
+	for word := range fstWords {
+		if _, ok := textWords[word]; !ok {
+			missingFromText = append(missingFromText, word)
+		}
+	}
+	sort.Strings(missingFromFST)
+	sort.Strings(missingFromText)
+	return missingFromFST, missingFromText, nil
+}
+
+// Close writes any unflushed changes and releases FST resources. With
+// WithJournal, changes are already in the journal and are left there for
+// the next load to replay.
 func (d *Dictionary) Close() error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	var flushErr error
+	if d.journalPath != "" {
+		flushErr = d.closeJournal()
+	} else if d.dirty {
+		flushErr = d.rebuildFST()
+	}
 	if d.fst != nil {
 		err := d.fst.Close()
 		d.fst = nil
-		return err
+		return errors.Join(flushErr, err)
 	}
-	return nil
+	return flushErr
+}
+
+// FSTVersion returns the header format version of the loaded FST.
+func (d *Dictionary) FSTVersion() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.fstVersion
+}
+
+// Generation returns a counter that changes whenever the word set is modified.
+// Callers caching lookup results can compare generations to detect staleness.
+func (d *Dictionary) Generation() uint64 {
+	return d.generation.Load()
 }
 
 // WordCount returns the number of words in the dictionary.
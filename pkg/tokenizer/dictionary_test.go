@@ -0,0 +1,937 @@
+package tokenizer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/vellum"
+)
+
+// newTestDictionary writes words to a temporary dictionary file and returns its path.
+// Use this for tests that modify the dictionary so the bundled file is left untouched.
+func newTestDictionary(t testing.TB, words ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "components.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(words, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write test dictionary: %v", err)
+	}
+	return path
+}
+
+func TestDictionary_Generation(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	before := dict.Generation()
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	afterAdd := dict.Generation()
+	if afterAdd == before {
+		t.Error("Expected generation to change after AddWord")
+	}
+
+	if err := dict.RemoveWord("konzept"); err != nil {
+		t.Fatalf("RemoveWord() error: %v", err)
+	}
+	if dict.Generation() == afterAdd {
+		t.Error("Expected generation to change after RemoveWord")
+	}
+}
+
+func TestDictionary_ContainsAfterClose(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	if !dict.Contains("brand") {
+		t.Fatal("Contains(\"brand\") = false before Close")
+	}
+
+	dict.Close()
+	if dict.Contains("brand") {
+		t.Error("Contains(\"brand\") = true after Close, want false")
+	}
+}
+
+func TestDictionary_WatchFile(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitter(dict)
+	if result := splitter.Split("brandschutzkonzept"); len(result) != 1 {
+		t.Fatalf("Split before reload = %v, want unsplit", result)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- dict.WatchFileInterval(ctx, 10*time.Millisecond) }()
+
+	// Edit the file out of band; bump mtime so the change is visible on coarse clocks
+	if err := os.WriteFile(dictPath, []byte("brand\nschutz\nkonzept\n"), 0o644); err != nil {
+		t.Fatalf("Failed to update dictionary file: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dictPath, future, future); err != nil {
+		t.Fatalf("Failed to update mtime: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !dict.Contains("konzept") {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if result := splitter.Split("brandschutzkonzept"); len(result) != 3 {
+		t.Errorf("Split after reload = %v, want [brand schutz konzept]", result)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("WatchFileInterval() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDictionary_WatchFileAfterFailedReload(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath, WithDictionaryLogger(slog.New(slog.DiscardHandler)))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- dict.WatchFileInterval(ctx, 10*time.Millisecond) }()
+
+	// A line longer than the scanner's buffer fails the reload
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(dictPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to update dictionary file: %v", err)
+		}
+		if err := os.Chtimes(dictPath, mtime, mtime); err != nil {
+			t.Fatalf("Failed to update mtime: %v", err)
+		}
+	}
+	write(strings.Repeat("x", 1<<17)+"\n", time.Now().Add(time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("WatchFileInterval() stopped after a failed reload: %v", err)
+	default:
+	}
+
+	write("brand\nschutz\nkonzept\n", time.Now().Add(2*time.Minute))
+	deadline := time.Now().Add(5 * time.Second)
+	for !dict.Contains("konzept") {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDictionary_ReloadKeepsTextFile(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	edited := "# Edited by hand\nschutz\nKonzept\nbrand\n"
+	if err := os.WriteFile(dictPath, []byte(edited), 0o644); err != nil {
+		t.Fatalf("Failed to update dictionary file: %v", err)
+	}
+	if err := dict.Reload(); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	if !dict.Contains("konzept") {
+		t.Error("Contains(\"konzept\") = false after Reload")
+	}
+
+	content, err := os.ReadFile(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to read dictionary file: %v", err)
+	}
+	if string(content) != edited {
+		t.Errorf("Reload() rewrote the text file to %q, want %q", content, edited)
+	}
+}
+
+func TestDictionary_FailedReloadKeepsFST(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if err := os.WriteFile(dictPath, []byte("konzept\n"), 0o644); err != nil {
+		t.Fatalf("Failed to update dictionary file: %v", err)
+	}
+	// A directory in place of the FST file makes the rebuild fail
+	fstPath := strings.TrimSuffix(dictPath, ".txt") + ".fst"
+	if err := os.Remove(fstPath); err != nil {
+		t.Fatalf("Failed to remove FST file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(fstPath, "blocker"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	if err := dict.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want error")
+	}
+	for word, want := range map[string]bool{"brand": true, "schutz": true, "konzept": false} {
+		if got := dict.Contains(word); got != want {
+			t.Errorf("Contains(%q) = %v after failed Reload, want %v", word, got, want)
+		}
+	}
+}
+
+func TestDictionary_FSTHeader(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	dict.Close()
+
+	if dict.FSTVersion() != FSTFormatVersion {
+		t.Errorf("FSTVersion() = %d, want %d", dict.FSTVersion(), FSTFormatVersion)
+	}
+
+	fstPath := strings.TrimSuffix(dictPath, ".txt") + ".fst"
+	header, err := readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	if header.WordCount != 3 {
+		t.Errorf("header.WordCount = %d, want 3", header.WordCount)
+	}
+}
+
+func TestDictionary_FSTHeaderVersionMismatchRebuilds(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	dict.Close()
+
+	// Pretend the FST was written by a future format version
+	fstPath := strings.TrimSuffix(dictPath, ".txt") + ".fst"
+	header, err := readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	header.Version = FSTFormatVersion + 1
+	if err := writeFSTHeader(headerPath(fstPath), header); err != nil {
+		t.Fatalf("writeFSTHeader() error: %v", err)
+	}
+
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	header, err = readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	if header.Version != FSTFormatVersion {
+		t.Errorf("Expected header to be rewritten with version %d, got %d", FSTFormatVersion, header.Version)
+	}
+	if !dict.Contains("schutz") {
+		t.Error("Expected rebuilt dictionary to contain 'schutz'")
+	}
+}
+
+func TestDictionary_FSTChecksumMismatchDetected(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	dict.Close()
+
+	// Corrupt the header checksum so it no longer matches the FST bytes
+	fstPath := strings.TrimSuffix(dictPath, ".txt") + ".fst"
+	header, err := readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	good := header.Checksum
+	header.Checksum = ^good
+	if err := writeFSTHeader(headerPath(fstPath), header); err != nil {
+		t.Fatalf("writeFSTHeader() error: %v", err)
+	}
+
+	stamp, err := statFile(dictPath)
+	if err != nil {
+		t.Fatalf("statFile() error: %v", err)
+	}
+	dict = &Dictionary{
+		fstPath: fstPath,
+		words:   map[string]struct{}{"brand": {}, "schutz": {}, "konzept": {}},
+		txtStat: stamp,
+	}
+	if dict.fstIsCurrent() {
+		t.Error("Expected checksum mismatch to be detected")
+	}
+
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	header, err = readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	if header.Checksum != good {
+		t.Errorf("Expected rebuild to restore checksum %08x, got %08x", good, header.Checksum)
+	}
+}
+
+func TestDictionary_SkipsRebuildWhenTextUnchanged(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	dict.Close()
+
+	// Restart with the text file untouched: the cached FST is reused
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	if dict.Generation() != 0 {
+		t.Error("Expected cached FST to be reused for an unchanged text file")
+	}
+	if !dict.Contains("konzept") {
+		t.Error("Expected cached FST to contain 'konzept'")
+	}
+	dict.Close()
+
+	// Edit the text file without changing the word count: the FST is rebuilt
+	if err := os.WriteFile(dictPath, []byte("brand\nschutz\ndecke\n"), 0o644); err != nil {
+		t.Fatalf("Failed to edit dictionary file: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dictPath, future, future); err != nil {
+		t.Fatalf("Failed to update mtime: %v", err)
+	}
+
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if dict.Generation() == 0 {
+		t.Error("Expected FST rebuild after the text file was edited")
+	}
+	if !dict.Contains("decke") || dict.Contains("konzept") {
+		t.Error("Expected rebuilt FST to reflect the edited text file")
+	}
+}
+
+func TestNewDictionaryInMemoryFST(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionaryInMemoryFST(dictPath)
+	if err != nil {
+		t.Fatalf("NewDictionaryInMemoryFST() error: %v", err)
+	}
+	defer dict.Close()
+
+	// Truncate and delete the FST file: a memory-mapped FST would break
+	fstPath := fstPathFor(dictPath)
+	if err := os.Truncate(fstPath, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(fstPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, word := range []string{"brand", "schutz"} {
+		if !dict.Contains(word) {
+			t.Errorf("Contains(%q) = false after deleting the FST file, want true", word)
+		}
+	}
+	if dict.Contains("haus") {
+		t.Error("Contains(\"haus\") = true, want false")
+	}
+
+	// A rebuild writes the FST again and loads the new one into memory
+	if _, err := dict.AddWords([]string{"haus"}); err != nil {
+		t.Fatalf("AddWords() error: %v", err)
+	}
+	if err := os.Remove(fstPath); err != nil {
+		t.Fatal(err)
+	}
+	if !dict.Contains("haus") {
+		t.Error("Contains(\"haus\") after rebuild = false, want true")
+	}
+}
+
+func TestDictionary_Gzip(t *testing.T) {
+	dir := t.TempDir()
+	dictPath := filepath.Join(dir, "components.txt.gz")
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("# components\nBrand\nschutz\n\nkonzept\n"))
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to compress dictionary: %v", err)
+	}
+	if err := os.WriteFile(dictPath, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to write dictionary: %v", err)
+	}
+
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load gzipped dictionary: %v", err)
+	}
+	for _, word := range []string{"brand", "schutz", "konzept"} {
+		if !dict.Contains(word) {
+			t.Errorf("Contains(%q) = false, want true", word)
+		}
+	}
+	if dict.Contains("components") {
+		t.Error("Comment line loaded as a word")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "components.fst")); err != nil {
+		t.Errorf("FST not cached next to the gzipped file: %v", err)
+	}
+
+	// Changes are saved compressed
+	if err := dict.AddWord("haus"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	dict.Close()
+
+	var words []string
+	if err := readWordFile(dictPath, func(word string) { words = append(words, word) }); err != nil {
+		t.Fatalf("Saved dictionary is not a readable gzip word list: %v", err)
+	}
+	expected := []string{"brand", "haus", "konzept", "schutz"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("Saved words = %v, want %v", words, expected)
+	}
+
+	// Restart with the file untouched: the cached FST is reused
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload gzipped dictionary: %v", err)
+	}
+	defer dict.Close()
+	if dict.Generation() != 0 {
+		t.Error("Expected cached FST to be reused for an unchanged gzipped file")
+	}
+	if !dict.Contains("haus") {
+		t.Error("Expected cached FST to contain 'haus'")
+	}
+}
+
+func TestDictionary_Merge(t *testing.T) {
+	base, err := NewDictionary(newTestDictionary(t, "brand", "schutz", "haus"))
+	if err != nil {
+		t.Fatalf("Failed to load base dictionary: %v", err)
+	}
+	defer base.Close()
+
+	overlay, err := NewDictionary(newTestDictionary(t, "haus", "konzept", "decke"))
+	if err != nil {
+		t.Fatalf("Failed to load overlay dictionary: %v", err)
+	}
+	defer overlay.Close()
+
+	generation := base.Generation()
+	added, err := base.Merge(overlay)
+	if err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("Merge() added = %d, want 2", added)
+	}
+	if base.WordCount() != 5 {
+		t.Errorf("WordCount() after merge = %d, want 5", base.WordCount())
+	}
+	if base.Generation() != generation+1 {
+		t.Errorf("Expected exactly one FST rebuild, generation went %d → %d", generation, base.Generation())
+	}
+	for _, word := range []string{"brand", "konzept", "decke"} {
+		if !base.Contains(word) {
+			t.Errorf("Expected merged dictionary to contain %q", word)
+		}
+	}
+
+	// Merging again adds nothing and doesn't rebuild
+	if added, err := base.Merge(overlay); err != nil || added != 0 {
+		t.Errorf("Second Merge() = %d, %v, want 0, nil", added, err)
+	}
+}
+
+func TestDictionary_Diff(t *testing.T) {
+	a, err := NewDictionary(newTestDictionary(t, "brand", "schutz", "haus"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer a.Close()
+
+	b, err := NewDictionary(newTestDictionary(t, "haus", "konzept", "decke"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer b.Close()
+
+	onlyA, onlyB := a.Diff(b)
+	if strings.Join(onlyA, ",") != "brand,schutz" {
+		t.Errorf("Diff() onlyA = %v, want [brand schutz]", onlyA)
+	}
+	if strings.Join(onlyB, ",") != "decke,konzept" {
+		t.Errorf("Diff() onlyB = %v, want [decke konzept]", onlyB)
+	}
+}
+
+func TestDictionary_EnableBloomFilter(t *testing.T) {
+	plain, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		t.Fatalf("NewDictionary() error: %v", err)
+	}
+	defer plain.Close()
+
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		t.Fatalf("NewDictionary() error: %v", err)
+	}
+	defer dict.Close()
+
+	if err := dict.EnableBloomFilter(0); err != nil {
+		t.Fatalf("EnableBloomFilter() error: %v", err)
+	}
+
+	// Every dictionary word is still found
+	for _, word := range dict.Words() {
+		if !dict.Contains(word) {
+			t.Fatalf("Contains(%q) = false with bloom filter, want true", word)
+		}
+	}
+
+	// Lookups of candidate prefixes agree with the unfiltered dictionary
+	for _, word := range []string{"brandschutzkonzept", "wärmedämmverbundsystem", "xyzabc"} {
+		runes := []rune(word)
+		for i := 0; i < len(runes); i++ {
+			for j := i + 1; j <= len(runes); j++ {
+				candidate := string(runes[i:j])
+				if got, want := dict.Contains(candidate), plain.Contains(candidate); got != want {
+					t.Errorf("Contains(%q) = %v with bloom filter, want %v", candidate, got, want)
+				}
+			}
+		}
+	}
+
+	for _, rate := range []float64{-0.1, 1, 2} {
+		if err := dict.EnableBloomFilter(rate); err == nil {
+			t.Errorf("EnableBloomFilter(%v) expected error", rate)
+		}
+	}
+}
+
+func TestDictionary_BloomFilterFollowsChanges(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "haus", "brand"))
+	if err != nil {
+		t.Fatalf("NewDictionary() error: %v", err)
+	}
+	defer dict.Close()
+
+	if err := dict.EnableBloomFilter(0.01); err != nil {
+		t.Fatalf("EnableBloomFilter() error: %v", err)
+	}
+
+	if err := dict.AddWord("schutz"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if !dict.Contains("schutz") {
+		t.Error("Contains(\"schutz\") = false after AddWord, bloom filter not rebuilt")
+	}
+}
+
+func TestDictionary_ImportExport(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "haus", "brand"))
+	if err != nil {
+		t.Fatalf("NewDictionary() error: %v", err)
+	}
+	defer dict.Close()
+
+	importPath := filepath.Join(t.TempDir(), "import.txt")
+	if err := os.WriteFile(importPath, []byte("# curated\nSchutz\nhaus\n\nkonzept\nschutz\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	generation := dict.Generation()
+	added, err := dict.Import(importPath)
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if added != 2 {
+		t.Errorf("Import() added = %d, want 2 (schutz, konzept)", added)
+	}
+	if got := dict.Generation() - generation; got != 1 {
+		t.Errorf("Import() rebuilt the FST %d times, want 1", got)
+	}
+	if !dict.Contains("schutz") || !dict.Contains("konzept") {
+		t.Error("Imported words missing from dictionary")
+	}
+
+	exportPath := filepath.Join(t.TempDir(), "export.txt")
+	if err := dict.Export(exportPath); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if want := "brand\nhaus\nkonzept\nschutz\n"; string(data) != want {
+		t.Errorf("Export() wrote %q, want %q", data, want)
+	}
+
+	if _, err := dict.Import(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Import() of a missing file expected error")
+	}
+}
+
+func TestDictionary_ValidateConsistency(t *testing.T) {
+	path := newTestDictionary(t, "haus", "brand", "schutz")
+	dict, err := NewDictionary(path)
+	if err != nil {
+		t.Fatalf("NewDictionary() error: %v", err)
+	}
+	defer dict.Close()
+
+	missingFromFST, missingFromText, err := dict.ValidateConsistency()
+	if err != nil {
+		t.Fatalf("ValidateConsistency() error: %v", err)
+	}
+	if len(missingFromFST) != 0 || len(missingFromText) != 0 {
+		t.Errorf("ValidateConsistency() = %v, %v on a fresh dictionary, want no differences", missingFromFST, missingFromText)
+	}
+
+	// Edit the text file without rebuilding the FST
+	if err := os.WriteFile(path, []byte("haus\nbrand\nkonzept\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	missingFromFST, missingFromText, err = dict.ValidateConsistency()
+	if err != nil {
+		t.Fatalf("ValidateConsistency() error: %v", err)
+	}
+	if strings.Join(missingFromFST, ",") != "konzept" {
+		t.Errorf("ValidateConsistency() missingFromFST = %v, want [konzept]", missingFromFST)
+	}
+	if strings.Join(missingFromText, ",") != "schutz" {
+		t.Errorf("ValidateConsistency() missingFromText = %v, want [schutz]", missingFromText)
+	}
+
+	if _, _, err := ValidateDictionaryFiles(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("ValidateDictionaryFiles() on a missing file expected error")
+	}
+}
+
+func TestDictionary_AddWordDefersRebuild(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if !dict.Contains("konzept") {
+		t.Error("Expected 'konzept' to be visible before Flush")
+	}
+	data, err := os.ReadFile(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to read dictionary file: %v", err)
+	}
+	if strings.Contains(string(data), "konzept") {
+		t.Error("Expected text file to be unchanged before Flush")
+	}
+
+	if err := dict.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+	data, err = os.ReadFile(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to read dictionary file: %v", err)
+	}
+	if !strings.Contains(string(data), "konzept") {
+		t.Error("Expected Flush to persist 'konzept'")
+	}
+	if !dict.Contains("konzept") {
+		t.Error("Expected rebuilt FST to contain 'konzept'")
+	}
+}
+
+func TestDictionary_CloseFlushes(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	if err := dict.RemoveWord("schutz"); err != nil {
+		t.Fatalf("RemoveWord() error: %v", err)
+	}
+	if dict.Contains("schutz") {
+		t.Error("Expected 'schutz' to be gone before Flush")
+	}
+	if err := dict.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	dict, err = NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer dict.Close()
+	if dict.Contains("schutz") {
+		t.Error("Expected Close to persist removal of 'schutz'")
+	}
+}
+
+func TestDictionary_IsDirtyTransitions(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if dict.IsDirty() {
+		t.Error("Expected freshly loaded dictionary to be clean")
+	}
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if !dict.IsDirty() {
+		t.Error("Expected dictionary to be dirty after AddWord")
+	}
+	if err := dict.RebuildFST(); err != nil {
+		t.Fatalf("RebuildFST() error: %v", err)
+	}
+	if dict.IsDirty() {
+		t.Error("Expected dictionary to be clean after RebuildFST")
+	}
+	if err := dict.RemoveWord("brand"); err != nil {
+		t.Fatalf("RemoveWord() error: %v", err)
+	}
+	if !dict.IsDirty() {
+		t.Error("Expected dictionary to be dirty after RemoveWord")
+	}
+	if err := dict.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+	if dict.IsDirty() {
+		t.Error("Expected dictionary to be clean after Flush")
+	}
+}
+
+func TestDictionary_AllWords(t *testing.T) {
+	dictPath := newTestDictionary(t, "schutz", "Brand", "wärme", "dämmung", "brand")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	expected := []string{"brand", "dämmung", "schutz", "wärme"}
+	result := dict.AllWords()
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("AllWords() = %v, want %v", result, expected)
+	}
+	if !sort.StringsAreSorted(result) {
+		t.Errorf("AllWords() = %v, want sorted", result)
+	}
+
+	// The FST view excludes unflushed changes until Flush
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if result := dict.AllWords(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("AllWords() before Flush = %v, want %v", result, expected)
+	}
+	if err := dict.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+	expected = []string{"brand", "dämmung", "konzept", "schutz", "wärme"}
+	if result := dict.AllWords(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("AllWords() after Flush = %v, want %v", result, expected)
+	}
+
+	dict.Close()
+	if result := dict.AllWords(); result != nil {
+		t.Errorf("AllWords() after Close = %v, want none", result)
+	}
+}
+
+func TestDictionary_StaleFSTRebuilt(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	dict.Close()
+
+	// Replace the FST with one holding other words but the same count, and
+	// fix up the header checksum so only the content is wrong
+	fstPath := fstPathFor(dictPath)
+	fstFile, err := os.Create(fstPath)
+	if err != nil {
+		t.Fatalf("Failed to create FST: %v", err)
+	}
+	builder, err := vellum.New(fstFile, nil)
+	if err != nil {
+		t.Fatalf("vellum.New() error: %v", err)
+	}
+	for _, word := range []string{"alpha", "beta", "gamma"} {
+		if err := builder.Insert([]byte(word), 0); err != nil {
+			t.Fatalf("Insert() error: %v", err)
+		}
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("builder.Close() error: %v", err)
+	}
+	fstFile.Close()
+
+	header, err := readFSTHeader(headerPath(fstPath))
+	if err != nil {
+		t.Fatalf("readFSTHeader() error: %v", err)
+	}
+	if header.Checksum, err = fileChecksum(fstPath); err != nil {
+		t.Fatalf("fileChecksum() error: %v", err)
+	}
+	if err := writeFSTHeader(headerPath(fstPath), header); err != nil {
+		t.Fatalf("writeFSTHeader() error: %v", err)
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	dict, err = NewDictionary(dictPath, WithDictionaryLogger(logger))
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if dict.Generation() == 0 {
+		t.Error("Expected stale FST to be rebuilt")
+	}
+	if !dict.Contains("brand") || dict.Contains("alpha") {
+		t.Error("Expected rebuilt FST to hold the text file's words")
+	}
+	if !strings.Contains(logs.String(), "FST rejected, rebuilding") {
+		t.Errorf("Expected rejection to be logged, got %q", logs.String())
+	}
+}
+
+// syntheticWords returns n distinct pseudo-random lowercase words,
+// the same ones on every call.
+func syntheticWords(n int) []string {
+	rng := rand.New(rand.NewPCG(1, 2))
+	seen := make(map[string]struct{}, n)
+	words := make([]string, 0, n)
+	buf := make([]byte, 0, 16)
+	for len(words) < n {
+		buf = buf[:0]
+		for range 4 + rng.IntN(11) {
+			buf = append(buf, byte('a'+rng.IntN(26)))
+		}
+		if _, ok := seen[string(buf)]; !ok {
+			seen[string(buf)] = struct{}{}
+			words = append(words, string(buf))
+		}
+	}
+	return words
+}
+
+func TestSortStrings(t *testing.T) {
+	for _, n := range []int{0, 10, parallelSortThreshold, 3*parallelSortThreshold + 7} {
+		input := syntheticWords(n)
+		expected := slices.Clone(input)
+		sort.Strings(expected)
+		for _, parts := range []int{1, 3, 4} {
+			words := slices.Clone(input)
+			sortStrings(words, parts)
+			if !slices.Equal(words, expected) {
+				t.Errorf("sortStrings(%d words, %d) differs from sort.Strings", n, parts)
+			}
+		}
+	}
+}
+
+func TestDictionary_RebuildFSTMatchesReference(t *testing.T) {
+	words := syntheticWords(parallelSortThreshold)
+	dictPath := newTestDictionary(t, words...)
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+	if err := dict.RebuildFST(); err != nil {
+		t.Fatalf("RebuildFST() error: %v", err)
+	}
+	got, err := os.ReadFile(fstPathFor(dictPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The straightforward build: sort.Strings and a fresh key per insert
+	sort.Strings(words)
+	var want bytes.Buffer
+	builder, err := vellum.New(&want, nil)
+	if err != nil {
+		t.Fatalf("vellum.New() error: %v", err)
+	}
+	for _, word := range words {
+		if err := builder.Insert([]byte(word), 0); err != nil {
+			t.Fatalf("Insert() error: %v", err)
+		}
+	}
+	if err := builder.Close(); err != nil {
+		t.Fatalf("builder.Close() error: %v", err)
+	}
+
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("RebuildFST() wrote %d bytes that differ from the reference build's %d", len(got), want.Len())
+	}
+}
@@ -0,0 +1,93 @@
+package tokenizer
+
+import "strings"
+
+// elisionConjunctions join a hyphenated stub to the full compound that
+// supplies its elided head: "Ein- und Ausgang", "Vor- oder Nachteil".
+var elisionConjunctions = map[string]bool{
+	"und":   true,
+	"oder":  true,
+	"bzw":   true,
+	"sowie": true,
+}
+
+// expandElidedStubs rewrites hyphenated stubs of coordinated compounds into
+// the full words they abbreviate: in "Ein- und Ausgang" the stub "Ein"
+// becomes "Eingang", taking the head from the compound after the
+// conjunction. Several stubs may precede the conjunction ("Ein-, Aus- und
+// Umgang"). Expanded tokens keep the stub's offsets. Stubs are left alone if
+// no conjunction follows or the final compound has no head, see elidedHead.
+func (t *Tokenizer) expandElidedStubs(raws []RawToken) []RawToken {
+	for i := 0; i < len(raws); i++ {
+		if !isElisionStub(raws, i) {
+			continue
+		}
+
+		stubs := []int{i}
+		next := nextWord(raws, i)
+		for next >= 0 && isElisionStub(raws, next) {
+			stubs = append(stubs, next)
+			next = nextWord(raws, next)
+		}
+		if next < 0 || !elisionConjunctions[strings.ToLower(raws[next].Text)] {
+			continue
+		}
+		full := nextWord(raws, next)
+		if full < 0 {
+			continue
+		}
+
+		head := t.elidedHead(raws[full].Text)
+		if head == "" {
+			continue
+		}
+		for _, s := range stubs {
+			raws[s].Text += head
+		}
+		i = full
+	}
+	return raws
+}
+
+// elidedHead returns the head the compound word supplies to elided stubs,
+// as spelled in word: everything after its first segment, so
+// "schutzkonzept" of "Brandschutzkonzept". A compound stored whole in the
+// dictionary, like "Ausgang" or "Nachteile", doesn't split; its head is its
+// longest proper suffix that is itself a dictionary word. It returns "" if
+// word has no head.
+func (t *Tokenizer) elidedHead(word string) string {
+	segments := t.split(word)
+	if len(segments) > 1 {
+		return strings.Join(t.sourceSegments(word, segments)[1:], "")
+	}
+	if !t.splitter.isValidWord(word) {
+		return ""
+	}
+	runes := []rune(word)
+	for i := t.splitter.minSegment; i <= len(runes)-t.splitter.minSegment; i++ {
+		if head := string(runes[i:]); t.splitter.isWordInDict(head) {
+			return head
+		}
+	}
+	return ""
+}
+
+// isElisionStub reports whether raws[i] is a word directly followed by a
+// hyphen and then a space or comma, like "Ein" in "Ein- und".
+func isElisionStub(raws []RawToken, i int) bool {
+	if i+1 >= len(raws) || raws[i].Type != TokenWord || raws[i+1].Type != TokenSeparator {
+		return false
+	}
+	sep := raws[i+1].Text
+	return len(sep) > 1 && sep[0] == '-' && strings.ContainsRune(" \t\n,", rune(sep[1]))
+}
+
+// nextWord returns the index of the first word token after i, or -1.
+func nextWord(raws []RawToken, i int) int {
+	for j := i + 1; j < len(raws); j++ {
+		if raws[j].Type == TokenWord {
+			return j
+		}
+	}
+	return -1
+}
@@ -0,0 +1,52 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer_ExpandElisions(t *testing.T) {
+	dictPath := newTestDictionary(t, "ein", "aus", "gang", "ausgang", "umgang", "vor", "nachteil", "teil", "teile", "brand", "schutz", "konzept")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.Normalizers.StemGerman = false
+	cfg.ExpandElisions = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []TokenSpan
+	}{
+		// "Ausgang" is a dictionary entry, so its head is its longest
+		// dictionary suffix; the expanded stub spans its own runes
+		{"Ein- und Ausgang", []TokenSpan{
+			{"ein", 0, 3}, {"gang", 0, 3}, {"und", 5, 8}, {"ausgang", 9, 16},
+		}},
+		// "Nachteile" only matches via suffix stripping, and "teile" wins
+		// over the shorter "eile"
+		{"Vor- oder Nachteile", []TokenSpan{
+			{"vor", 0, 3}, {"teile", 0, 3}, {"oder", 5, 9}, {"nachteile", 10, 19},
+		}},
+		{"Ein-, Aus- und Umgang", []TokenSpan{
+			{"ein", 0, 3}, {"gang", 0, 3}, {"ausgang", 6, 9}, {"und", 11, 14}, {"umgang", 15, 21},
+		}},
+		// A compound that splits supplies everything after its first segment
+		{"Brand- und Schutzkonzept", []TokenSpan{
+			{"brand", 0, 5}, {"konzept", 0, 5}, {"und", 7, 10}, {"schutz", 11, 17}, {"konzept", 17, 24},
+		}},
+		// No head, or no conjunction
+		{"Ein- und Xyz", []TokenSpan{{"ein", 0, 3}, {"und", 5, 8}, {"xyz", 9, 12}}},
+		{"Ein- Ausgang", []TokenSpan{{"ein", 0, 3}, {"ausgang", 5, 12}}},
+	}
+
+	for _, tt := range tests {
+		result := tok.TokenizeWithSpans(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("TokenizeWithSpans(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
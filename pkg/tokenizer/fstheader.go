@@ -0,0 +1,89 @@
+package tokenizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// FSTFormatVersion is the version of the FST header format written by this package.
+// Bump it whenever the on-disk layout changes so stale files are rebuilt.
+const FSTFormatVersion = 2
+
+// fstMagic identifies a German tokenizer FST header file.
+var fstMagic = [4]byte{'G', 'T', 'F', 'H'}
+
+// errBadFSTMagic is returned when a header file doesn't start with fstMagic.
+var errBadFSTMagic = errors.New("not a tokenizer FST header")
+
+// fstHeader is stored next to the FST file and describes how it was built.
+// vellum FSTs carry no metadata of their own, so without it a stale or
+// corrupt FST would be loaded silently.
+type fstHeader struct {
+	Magic       [4]byte
+	Version     uint32
+	WordCount   uint64
+	Checksum    uint32 // CRC-32 (IEEE) of the FST file
+	TextSize    int64  // Size of the text file the FST was built from
+	TextModTime int64  // Modification time (Unix nanoseconds) of that text file
+}
+
+// matchesText reports whether the header was built from the text file version stamp.
+func (h fstHeader) matchesText(stamp fileStamp) bool {
+	return h.TextSize == stamp.size && h.TextModTime == stamp.modTime.UnixNano()
+}
+
+// headerPath returns the path of the header file for an FST file.
+func headerPath(fstPath string) string {
+	return fstPath + ".meta"
+}
+
+// readFSTHeader reads and checks the magic of the header at path.
+func readFSTHeader(path string) (fstHeader, error) {
+	var h fstHeader
+
+	file, err := os.Open(path)
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+
+	if err := binary.Read(file, binary.LittleEndian, &h); err != nil {
+		return h, err
+	}
+	if h.Magic != fstMagic {
+		return h, errBadFSTMagic
+	}
+	return h, nil
+}
+
+// writeFSTHeader writes h to path.
+func writeFSTHeader(path string, h fstHeader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := binary.Write(file, binary.LittleEndian, h); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// fileChecksum returns the CRC-32 (IEEE) of the file at path.
+func fileChecksum(path string) (uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	hash := crc32.NewIEEE()
+	if _, err := io.Copy(hash, file); err != nil {
+		return 0, err
+	}
+	return hash.Sum32(), nil
+}
@@ -0,0 +1,154 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Journal entry prefixes for added and removed words.
+const (
+	journalAdd    = '+'
+	journalRemove = '-'
+)
+
+// WithJournal makes AddWord and RemoveWord append each change to a journal
+// file next to the text file ("components.txt.journal") instead of leaving
+// it for a full rewrite of the text file on Flush or Close. The journal is
+// replayed on load, so journaled words survive a restart without the text
+// file or FST being rewritten.
+//
+// Compact merges the journal into the text file and FST. It also runs
+// automatically once the journal holds compactAfter entries; 0 leaves
+// compaction to explicit Compact calls and other rebuilds such as AddWords
+// and RebuildFST, which clear the journal as well.
+func WithJournal(compactAfter int) DictionaryOption {
+	return func(d *Dictionary) {
+		d.journalPath = d.txtPath + ".journal"
+		d.compactAfter = max(compactAfter, 0)
+	}
+}
+
+// replayJournal applies the journal's changes to the word set (caller must
+// hold lock or own d). Entries are idempotent, so a journal left behind by a
+// compaction interrupted after rewriting the text file replays harmlessly.
+// Entries without a word, such as a bare "+", are skipped like entries with
+// an unknown prefix.
+func (d *Dictionary) replayJournal() error {
+	entries := 0
+	err := readWordFile(d.journalPath, func(entry string) {
+		if len(entry) < 2 {
+			return
+		}
+		word := strings.ToLower(entry[1:])
+		switch entry[0] {
+		case journalAdd:
+			d.words[word] = struct{}{}
+		case journalRemove:
+			delete(d.words, word)
+		default:
+			return
+		}
+		entries++
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("replay journal %s: %w", d.journalPath, err)
+	}
+
+	d.journalEntries = entries
+	if entries > 0 {
+		d.logger.Info("replayed dictionary journal", "path", d.journalPath, "entries", entries)
+		d.rebuildBloom()
+		d.markDirty()
+	}
+	return nil
+}
+
+// appendJournal appends a change to the journal (caller must hold lock).
+// The file is opened on first use and kept open until compaction or Close.
+func (d *Dictionary) appendJournal(op byte, word string) error {
+	if d.journalFile == nil {
+		file, err := os.OpenFile(d.journalPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		d.journalFile = file
+	}
+	if _, err := d.journalFile.WriteString(string(op) + word + "\n"); err != nil {
+		return err
+	}
+	d.journalEntries++
+	return nil
+}
+
+// journalChange records a change to the word set in the journal before it
+// is applied (caller must hold lock). It is a no-op without WithJournal.
+func (d *Dictionary) journalChange(op byte, word string) error {
+	if d.journalPath == "" {
+		return nil
+	}
+	if err := d.appendJournal(op, word); err != nil {
+		return fmt.Errorf("append to journal %s: %w", d.journalPath, err)
+	}
+	return nil
+}
+
+// compactIfDue compacts once the journal has grown to compactAfter entries
+// (caller must hold lock).
+func (d *Dictionary) compactIfDue() error {
+	if d.compactAfter > 0 && d.journalEntries >= d.compactAfter {
+		return d.rebuildFST()
+	}
+	return nil
+}
+
+// clearJournal closes and removes the journal once the text file holds all
+// of its changes (caller must hold lock).
+func (d *Dictionary) clearJournal() error {
+	if d.journalPath == "" {
+		return nil
+	}
+	err := d.closeJournal()
+	if rmErr := os.Remove(d.journalPath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		err = errors.Join(err, rmErr)
+	}
+	d.journalEntries = 0
+	return err
+}
+
+// closeJournal closes the journal file if it is open (caller must hold lock).
+func (d *Dictionary) closeJournal() error {
+	if d.journalFile == nil {
+		return nil
+	}
+	err := d.journalFile.Close()
+	d.journalFile = nil
+	return err
+}
+
+// Compact merges the journal into the text file and rebuilds the FST, then
+// removes the journal. It is a no-op if there is nothing to merge.
+func (d *Dictionary) Compact() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDictionaryClosed
+	}
+	if !d.dirty && d.journalEntries == 0 {
+		return nil
+	}
+	return d.rebuildFST()
+}
+
+// JournalEntries returns the number of changes in the journal awaiting
+// compaction.
+func (d *Dictionary) JournalEntries() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.journalEntries
+}
@@ -0,0 +1,157 @@
+package tokenizer
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDictionary_JournalReplayedOnLoad(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "haus", "schutz")
+	before, err := os.ReadFile(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to read text file: %v", err)
+	}
+
+	dict, err := NewDictionary(dictPath, WithJournal(0))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	if err := dict.AddWord("Konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if err := dict.RemoveWord("haus"); err != nil {
+		t.Fatalf("RemoveWord() error: %v", err)
+	}
+	if n := dict.JournalEntries(); n != 2 {
+		t.Errorf("JournalEntries() = %d, want 2", n)
+	}
+	if err := dict.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	// Close leaves the text file alone; the changes live in the journal
+	after, err := os.ReadFile(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to read text file: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("Text file rewritten with journal enabled:\n%s", after)
+	}
+
+	reloaded, err := NewDictionary(dictPath, WithJournal(0))
+	if err != nil {
+		t.Fatalf("Failed to reload dictionary: %v", err)
+	}
+	defer reloaded.Close()
+
+	if !reloaded.Contains("konzept") {
+		t.Error("Journaled word not found after reload")
+	}
+	if reloaded.Contains("haus") {
+		t.Error("Journaled removal not applied after reload")
+	}
+	if n := reloaded.JournalEntries(); n != 2 {
+		t.Errorf("JournalEntries() after reload = %d, want 2", n)
+	}
+}
+
+func TestDictionary_JournalSkipsEntriesWithoutWord(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	// Build the FST first; building it clears the journal
+	dict, err := NewDictionary(dictPath, WithJournal(0))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	dict.Close()
+	if err := os.WriteFile(dictPath+".journal", []byte("+\n+konzept\n-\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write journal: %v", err)
+	}
+
+	dict, err = NewDictionary(dictPath, WithJournal(0))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if n := dict.JournalEntries(); n != 1 {
+		t.Errorf("JournalEntries() = %d, want 1", n)
+	}
+	expected := []string{"brand", "konzept", "schutz"}
+	if result := dict.Words(); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Words() = %q, want %q", result, expected)
+	}
+}
+
+func TestDictionary_Compact(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	dict, err := NewDictionary(dictPath, WithJournal(0))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if err := dict.Compact(); err != nil {
+		t.Fatalf("Compact() error: %v", err)
+	}
+
+	if n := dict.JournalEntries(); n != 0 {
+		t.Errorf("JournalEntries() after Compact = %d, want 0", n)
+	}
+	if _, err := os.Stat(dictPath + ".journal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Journal still exists after Compact: %v", err)
+	}
+	if dict.IsDirty() {
+		t.Error("IsDirty() = true after Compact")
+	}
+
+	// The text file and FST hold the merged words without the journal
+	expected := []string{"brand", "konzept", "schutz"}
+	if words := dict.AllWords(); !reflect.DeepEqual(words, expected) {
+		t.Errorf("AllWords() after Compact = %v, want %v", words, expected)
+	}
+	missingFromFST, missingFromText, err := ValidateDictionaryFiles(dictPath)
+	if err != nil || missingFromFST != nil || missingFromText != nil {
+		t.Errorf("ValidateDictionaryFiles() = %v, %v, %v, want consistent files", missingFromFST, missingFromText, err)
+	}
+
+	// Compacting again is a no-op
+	if err := dict.Compact(); err != nil {
+		t.Errorf("Compact() with empty journal error: %v", err)
+	}
+}
+
+func TestDictionary_JournalCompactAfter(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand")
+	dict, err := NewDictionary(dictPath, WithJournal(2))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	if err := dict.AddWord("haus"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if n := dict.JournalEntries(); n != 1 {
+		t.Errorf("JournalEntries() = %d, want 1", n)
+	}
+	if err := dict.AddWord("schutz"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if n := dict.JournalEntries(); n != 0 {
+		t.Errorf("JournalEntries() after reaching compactAfter = %d, want 0", n)
+	}
+
+	expected := []string{"brand", "haus", "schutz"}
+	var words []string
+	if err := readWordFile(dictPath, func(word string) { words = append(words, word) }); err != nil {
+		t.Fatalf("Failed to read text file: %v", err)
+	}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("Text file after compaction = %v, want %v", words, expected)
+	}
+}
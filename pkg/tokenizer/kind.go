@@ -0,0 +1,80 @@
+package tokenizer
+
+import "fmt"
+
+// TokenKind describes how an output token was produced from its word.
+type TokenKind int
+
+const (
+	KindOriginal TokenKind = iota // Lowercase original word, umlauts kept: "wärme"
+	KindSegment                   // Normalized word or compound segment: "warm"
+	KindWhole                     // Normalized whole compound (AlwaysEmitWhole)
+	KindVariant                   // Alternate spelling (EmitEszettVariants, EmitVariants)
+	KindNgram                     // Character n-gram (SubwordFallback)
+	KindSymbol                    // Symbol token such as "€" (Symbols)
+)
+
+// String returns the kind name.
+func (k TokenKind) String() string {
+	switch k {
+	case KindOriginal:
+		return "original"
+	case KindSegment:
+		return "segment"
+	case KindWhole:
+		return "whole"
+	case KindVariant:
+		return "variant"
+	case KindNgram:
+		return "ngram"
+	case KindSymbol:
+		return "symbol"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the kind as its name, so it reads as a string in JSON.
+func (k TokenKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind name produced by MarshalText.
+func (k *TokenKind) UnmarshalText(text []byte) error {
+	for kind := KindOriginal; kind <= KindSymbol; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown token kind %q", text)
+}
+
+// TypedToken is an output token with the kind of form it is, for telling
+// the original spelling of a word apart from its normalized forms.
+type TypedToken struct {
+	Token string    `json:"token"`
+	Kind  TokenKind `json:"kind"`
+}
+
+// TokenizeTyped is like Tokenize but reports the kind of each token:
+// "Wärme" yields {"wärme", KindOriginal} and {"warm", KindSegment}.
+// Like Tokenize, tokens are deduplicated; each keeps the kind of the
+// occurrence that first produced it.
+func (t *Tokenizer) TokenizeTyped(text string) []TypedToken {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(tokenSet)
+	var results []TypedToken
+
+	for _, raw := range t.splitWords(text) {
+		t.tokenizeRaw(raw, func(token string, origin tokenOrigin) {
+			if seen.add(token) {
+				results = append(results, TypedToken{Token: token, Kind: origin.kind})
+			}
+		})
+	}
+
+	return results
+}
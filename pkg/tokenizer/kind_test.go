@@ -0,0 +1,74 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer_TokenizeTyped(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "wärme")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = true
+	cfg.AlwaysEmitWhole = true
+	cfg.EmitVariants = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []TypedToken
+	}{
+		{"Brandschutz", []TypedToken{
+			{"brandschutz", KindOriginal},
+			{"brand", KindSegment},
+			{"schutz", KindSegment},
+		}},
+		{"Wärme", []TypedToken{
+			{"wärme", KindOriginal},
+			{"warme", KindSegment},
+			{"waerme", KindVariant},
+		}},
+	}
+
+	for _, tt := range tests {
+		result := tok.TokenizeTyped(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("TokenizeTyped(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenKind_MarshalText(t *testing.T) {
+	data, err := json.Marshal(TypedToken{Token: "warm", Kind: KindSegment})
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if expected := `{"token":"warm","kind":"segment"}`; string(data) != expected {
+		t.Errorf("json.Marshal() = %s, want %s", data, expected)
+	}
+}
+
+func TestTokenKind_UnmarshalText(t *testing.T) {
+	for _, kind := range []TokenKind{KindOriginal, KindSegment, KindWhole, KindVariant, KindNgram, KindSymbol} {
+		data, err := json.Marshal(TypedToken{Token: "warm", Kind: kind})
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) error: %v", kind, err)
+		}
+		var decoded TypedToken
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+		}
+		if decoded.Kind != kind {
+			t.Errorf("json.Unmarshal(%s) kind = %v, want %v", data, decoded.Kind, kind)
+		}
+	}
+
+	var kind TokenKind
+	if err := kind.UnmarshalText([]byte("unknown")); err == nil {
+		t.Error("UnmarshalText(\"unknown\") error = nil, want error")
+	}
+}
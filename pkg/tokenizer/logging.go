@@ -0,0 +1,31 @@
+package tokenizer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// nopHandler is a slog.Handler that discards all records. It backs the
+// default logger so log points need no nil checks.
+type nopHandler struct{}
+
+func (nopHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (nopHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h nopHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h nopHandler) WithGroup(string) slog.Handler           { return h }
+
+// nopLogger is the logger used when none is configured.
+var nopLogger = slog.New(nopHandler{})
+
+// DictionaryOption configures optional Dictionary behavior.
+type DictionaryOption func(*Dictionary)
+
+// WithDictionaryLogger sends dictionary events, such as FST rebuilds and
+// rejected FST files, to logger. Nil keeps the dictionary silent.
+func WithDictionaryLogger(logger *slog.Logger) DictionaryOption {
+	return func(d *Dictionary) {
+		if logger != nil {
+			d.logger = logger
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package tokenizer
+
+import "time"
+
+// Metrics receives tokenizer measurements for export to a monitoring system
+// such as Prometheus, without this package depending on a client library.
+// Methods are called on the tokenization hot path, so implementations must
+// be fast and safe for concurrent use. A nil Metrics disables all hooks.
+type Metrics interface {
+	// TokensProduced is called once per Tokenize call with the number of
+	// tokens returned.
+	TokensProduced(n int)
+
+	// SplitObserved is called once per Split with its duration and whether
+	// the result was served from the cache.
+	SplitObserved(d time.Duration, cached bool)
+
+	// SplitFallback is called by Tokenize for each word that neither split
+	// nor matched the dictionary.
+	SplitFallback()
+}
@@ -0,0 +1,71 @@
+package tokenizer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMetrics records every hook call.
+type fakeMetrics struct {
+	mu          sync.Mutex
+	tokenCalls  []int
+	splitHits   int
+	splitMisses int
+	fallbacks   int
+}
+
+func (m *fakeMetrics) TokensProduced(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokenCalls = append(m.tokenCalls, n)
+}
+
+func (m *fakeMetrics) SplitObserved(_ time.Duration, cached bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cached {
+		m.splitHits++
+	} else {
+		m.splitMisses++
+	}
+}
+
+func (m *fakeMetrics) SplitFallback() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallbacks++
+}
+
+func TestTokenizer_Metrics(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	metrics := &fakeMetrics{}
+	cfg := testConfig()
+	cfg.Metrics = metrics
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Brandschutz Xyzabc Brandschutz")
+
+	if len(metrics.tokenCalls) != 1 || metrics.tokenCalls[0] != len(result) {
+		t.Errorf("TokensProduced calls = %v, want [%d]", metrics.tokenCalls, len(result))
+	}
+	if metrics.splitMisses != 2 || metrics.splitHits != 1 {
+		t.Errorf("SplitObserved misses/hits = %d/%d, want 2/1", metrics.splitMisses, metrics.splitHits)
+	}
+	if metrics.fallbacks != 1 {
+		t.Errorf("SplitFallback calls = %d, want 1", metrics.fallbacks)
+	}
+
+	// Hooks survive a dictionary reload
+	if err := tok.ReloadDictionary(dictPath); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+	tok.Tokenize("Brandschutz")
+	if metrics.splitMisses != 3 {
+		t.Errorf("SplitObserved misses after reload = %d, want 3", metrics.splitMisses)
+	}
+}
@@ -0,0 +1,101 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DictionarySource is a named WordLookup in a MultiDictionary.
+type DictionarySource struct {
+	Name   string
+	Lookup WordLookup
+}
+
+// MultiDictionary combines several word lookups into one WordLookup,
+// consulting them in priority order. A word is contained if any source
+// contains it, and Source reports the first source that does, e.g. to weight
+// matches from a domain dictionary above those from a base dictionary.
+//
+// It can be passed to the CompoundSplitter like any other WordLookup. Cached
+// splits are recomputed when any source with a Generation changes.
+type MultiDictionary struct {
+	sources []DictionarySource
+	owned   []*Dictionary // Opened by NewMultiDictionary and closed by Close
+}
+
+// NewMultiDictionary opens the dictionaries at paths, highest priority first.
+// Each source is named after its file without the extension, so
+// "dicts/domain.txt" becomes "domain".
+func NewMultiDictionary(paths ...string) (*MultiDictionary, error) {
+	m := &MultiDictionary{}
+	for _, path := range paths {
+		dict, err := NewDictionary(path)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("failed to open dictionary %s: %w", path, err)
+		}
+		m.owned = append(m.owned, dict)
+		m.sources = append(m.sources, DictionarySource{Name: sourceName(path), Lookup: dict})
+	}
+	return m, nil
+}
+
+// NewMultiDictionaryFromSources combines existing lookups, highest priority
+// first. The caller remains responsible for closing them.
+func NewMultiDictionaryFromSources(sources ...DictionarySource) *MultiDictionary {
+	return &MultiDictionary{sources: append([]DictionarySource(nil), sources...)}
+}
+
+// sourceName derives a source name from a dictionary path.
+func sourceName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Contains reports whether any source contains word.
+func (m *MultiDictionary) Contains(word string) bool {
+	_, ok := m.Source(word)
+	return ok
+}
+
+// Source returns the name of the highest-priority source containing word.
+func (m *MultiDictionary) Source(word string) (name string, ok bool) {
+	for _, src := range m.sources {
+		if src.Lookup.Contains(word) {
+			return src.Name, true
+		}
+	}
+	return "", false
+}
+
+// Sources returns the source names in priority order.
+func (m *MultiDictionary) Sources() []string {
+	names := make([]string, len(m.sources))
+	for i, src := range m.sources {
+		names[i] = src.Name
+	}
+	return names
+}
+
+// Generation returns a counter that changes whenever a versioned source
+// changes. Sources without a Generation are treated as immutable.
+func (m *MultiDictionary) Generation() uint64 {
+	var gen uint64
+	for _, src := range m.sources {
+		if v, ok := src.Lookup.(versionedLookup); ok {
+			gen += v.Generation()
+		}
+	}
+	return gen
+}
+
+// Close closes the dictionaries opened by NewMultiDictionary.
+func (m *MultiDictionary) Close() error {
+	var errs []error
+	for _, dict := range m.owned {
+		errs = append(errs, dict.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package tokenizer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMultiDictionary(t *testing.T) {
+	dir := t.TempDir()
+	overlayPath := filepath.Join(dir, "domain.txt")
+	basePath := filepath.Join(dir, "base.txt")
+	if err := os.WriteFile(overlayPath, []byte("photovoltaik\nanlage\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write overlay: %v", err)
+	}
+	if err := os.WriteFile(basePath, []byte("anlage\nbetreiber\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write base: %v", err)
+	}
+
+	multi, err := NewMultiDictionary(overlayPath, basePath)
+	if err != nil {
+		t.Fatalf("NewMultiDictionary() error: %v", err)
+	}
+	defer multi.Close()
+
+	if names := multi.Sources(); !reflect.DeepEqual(names, []string{"domain", "base"}) {
+		t.Errorf("Sources() = %v, want [domain base]", names)
+	}
+
+	tests := []struct {
+		word   string
+		source string
+		ok     bool
+	}{
+		{"photovoltaik", "domain", true}, // Only in the overlay
+		{"anlage", "domain", true},       // In both: the overlay wins
+		{"betreiber", "base", true},      // Only in the base
+		{"Betreiber", "base", true},
+		{"haus", "", false},
+	}
+
+	for _, tt := range tests {
+		source, ok := multi.Source(tt.word)
+		if source != tt.source || ok != tt.ok {
+			t.Errorf("Source(%q) = %q, %v, want %q, %v", tt.word, source, ok, tt.source, tt.ok)
+		}
+		if contains := multi.Contains(tt.word); contains != tt.ok {
+			t.Errorf("Contains(%q) = %v, want %v", tt.word, contains, tt.ok)
+		}
+	}
+
+	// The splitter combines segments from both sources
+	splitter := NewCompoundSplitter(multi)
+	expected := []string{"photovoltaik", "anlage", "betreiber"}
+	if result := splitter.Split("Photovoltaikanlagebetreiber"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Split(%q) = %v, want %v", "Photovoltaikanlagebetreiber", result, expected)
+	}
+}
+
+func TestMultiDictionary_Generation(t *testing.T) {
+	base, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer base.Close()
+
+	overlay := &setLookup{words: map[string]bool{"konzept": true}}
+	multi := NewMultiDictionaryFromSources(
+		DictionarySource{Name: "overlay", Lookup: overlay},
+		DictionarySource{Name: "base", Lookup: base},
+	)
+
+	splitter := NewCompoundSplitter(multi)
+	if result := splitter.Split("brandmelder"); len(result) != 1 {
+		t.Fatalf("Split(%q) = %v, want unsplit", "brandmelder", result)
+	}
+
+	// Adding to a source invalidates cached splits
+	if err := base.AddWord("melder"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	expected := []string{"brand", "melder"}
+	if result := splitter.Split("brandmelder"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Split(%q) after AddWord = %v, want %v", "brandmelder", result, expected)
+	}
+}
+
+func TestNewMultiDictionary_MissingFile(t *testing.T) {
+	if _, err := NewMultiDictionary(newTestDictionary(t, "haus"), filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("NewMultiDictionary() with a missing file: expected error")
+	}
+}
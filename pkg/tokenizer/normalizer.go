@@ -1,10 +1,19 @@
 package tokenizer
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/kljensen/snowball"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 	"golang.org/x/text/unicode/norm"
 )
 
@@ -14,37 +23,175 @@ type NormalizerFunc func(string) string
 // Normalizer applies a configurable pipeline of normalization steps.
 type Normalizer struct {
 	steps []NormalizerFunc
+	names []string
+	ascii []asciiStep // Per step, how to apply it to ASCII input
+}
+
+// asciiStep is the ASCII fast path of a normalization step.
+type asciiStep struct {
+	known bool           // The step's effect on ASCII input is known
+	fn    NormalizerFunc // Cheaper equivalent on ASCII input, nil if it is a no-op
+}
+
+// asciiShortcuts maps built-in steps to their ASCII fast path: nil for steps
+// that never change ASCII input, or a cheaper equivalent. Results stay ASCII.
+var asciiShortcuts = map[uintptr]NormalizerFunc{
+	funcPointer(NFKDDecompose):               nil,
+	funcPointer(NFCNormalize):                nil,
+	funcPointer(NFDNormalize):                nil,
+	funcPointer(NFKCNormalize):               nil,
+	funcPointer(RemoveFormatChars):           nil,
+	funcPointer(NormalizeQuotes):             nil,
+	funcPointer(ExpandLigatures):             nil,
+	funcPointer(ExpandArchaic):               nil,
+	funcPointer(NormalizeHistoricalSpelling): NormalizeHistoricalSpelling,
+	funcPointer(ConvertEszett):               nil,
+	funcPointer(UmlautToDigraph):             nil,
+	funcPointer(RemoveCombiningMarks):        nil,
+	funcPointer(RemoveUmlautMarks):           nil,
+	funcPointer(RemoveAccentMarks):           nil,
+	funcPointer(RemoveControlChars):          removeASCIIControlChars,
+	funcPointer(Lowercase):                   Lowercase, // strings.ToLower has its own ASCII path
+	funcPointer(LowercaseGerman):             Lowercase,
+}
+
+// funcPointer returns the code pointer identifying a function.
+func funcPointer(fn NormalizerFunc) uintptr {
+	return reflect.ValueOf(fn).Pointer()
+}
+
+// asciiSteps looks up the ASCII fast path of each step.
+func asciiSteps(steps []NormalizerFunc) []asciiStep {
+	ascii := make([]asciiStep, len(steps))
+	for i, step := range steps {
+		ascii[i].fn, ascii[i].known = asciiShortcuts[funcPointer(step)]
+	}
+	return ascii
+}
+
+// isASCII reports whether s contains only ASCII bytes.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// StepResult records the output of a single normalization step.
+type StepResult struct {
+	Name   string
+	Output string
+}
+
+// namedStep pairs a normalization step with its trace name.
+type namedStep struct {
+	name string
+	fn   NormalizerFunc
 }
 
 // NewNormalizer creates a normalizer with the default pipeline.
 func NewNormalizer() *Normalizer {
-	return &Normalizer{
-		steps: []NormalizerFunc{
-			NFKDDecompose,
-			RemoveControlChars,
-			Lowercase,
-			NormalizeQuotes,
-			ExpandLigatures,
-			ConvertEszett,
-			RemoveCombiningMarks,
-			StemGerman,
-		},
-	}
+	return NewNormalizerWithSteps(
+		NFKDDecompose,
+		RemoveControlChars,
+		Lowercase,
+		NormalizeQuotes,
+		ExpandLigatures,
+		ConvertEszett,
+		RemoveCombiningMarks,
+		StemGerman,
+	)
 }
 
+// ErrNilStep is returned by NewNormalizerWithStepsSafe for a nil step.
+var ErrNilStep = errors.New("normalizer step is nil")
+
 // NewNormalizerWithSteps creates a normalizer with a custom pipeline.
+// Steps must be non-nil; it panics naming the first nil step otherwise.
+// Use NewNormalizerWithStepsSafe to get an error instead.
 func NewNormalizerWithSteps(steps ...NormalizerFunc) *Normalizer {
-	return &Normalizer{steps: steps}
+	n, err := NewNormalizerWithStepsSafe(steps...)
+	if err != nil {
+		panic("tokenizer: NewNormalizerWithSteps: " + err.Error())
+	}
+	return n
+}
+
+// NewNormalizerWithStepsSafe is like NewNormalizerWithSteps but returns an
+// error wrapping ErrNilStep, with the step's index, if a step is nil.
+func NewNormalizerWithStepsSafe(steps ...NormalizerFunc) (*Normalizer, error) {
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		if step == nil {
+			return nil, fmt.Errorf("step %d: %w", i, ErrNilStep)
+		}
+		names[i] = stepName(step)
+	}
+	return &Normalizer{steps: steps, names: names, ascii: asciiSteps(steps)}, nil
+}
+
+// newNamedNormalizer creates a normalizer whose trace uses explicit step names.
+func newNamedNormalizer(steps []namedStep) *Normalizer {
+	n := &Normalizer{
+		steps: make([]NormalizerFunc, len(steps)),
+		names: make([]string, len(steps)),
+	}
+	for i, step := range steps {
+		n.steps[i] = step.fn
+		n.names[i] = step.name
+	}
+	n.ascii = asciiSteps(n.steps)
+	return n
+}
+
+// stepName derives a trace name from a function's identity, e.g. "Lowercase".
+func stepName(fn NormalizerFunc) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	return name[strings.LastIndex(name, ".")+1:]
 }
 
 // Normalize applies all configured steps in order.
+//
+// While the string is pure ASCII, built-in steps that can't change it, such
+// as NFKD decomposition and combining mark removal, are skipped, and others
+// use cheaper ASCII equivalents. The output is the same either way.
 func (n *Normalizer) Normalize(s string) string {
-	for _, step := range n.steps {
+	ascii := isASCII(s)
+	for i, step := range n.steps {
+		if !ascii {
+			s = step(s)
+			continue
+		}
+		if fast := n.ascii[i]; fast.known {
+			if fast.fn != nil {
+				s = fast.fn(s)
+			}
+			continue
+		}
+		// Custom steps may introduce non-ASCII runes
 		s = step(s)
+		ascii = isASCII(s)
 	}
 	return s
 }
 
+// NormalizeTrace applies all configured steps in order and records the
+// intermediate string produced by each one.
+func (n *Normalizer) NormalizeTrace(s string) []StepResult {
+	trace := make([]StepResult, len(n.steps))
+	for i, step := range n.steps {
+		s = step(s)
+		trace[i] = StepResult{Name: n.names[i], Output: s}
+	}
+	return trace
+}
+
 // LowercaseOnly lowercases without other transformations (preserves umlauts).
 func (n *Normalizer) LowercaseOnly(s string) string {
 	return strings.ToLower(s)
@@ -56,6 +203,53 @@ func NFKDDecompose(s string) string {
 	return norm.NFKD.String(s)
 }
 
+// NFCNormalize applies Unicode NFC normalization, composing a + combining
+// umlaut into ä and leaving compatibility characters such as "②" alone.
+func NFCNormalize(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NFDNormalize applies Unicode NFD normalization, decomposing ä into a +
+// combining umlaut but leaving compatibility characters alone.
+func NFDNormalize(s string) string {
+	return norm.NFD.String(s)
+}
+
+// NFKCNormalize applies Unicode NFKC normalization: compatibility characters
+// are replaced ("②" → "2", "ﬁ" → "fi") but umlauts stay precomposed.
+func NFKCNormalize(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// NormalizationForm selects the Unicode normalization form step.
+type NormalizationForm int
+
+const (
+	// FormNone adds no normalization form step of its own; NFKDDecompose
+	// still applies if set.
+	FormNone NormalizationForm = iota
+	FormNFC
+	FormNFD
+	FormNFKC
+	FormNFKD
+)
+
+// step returns the named step for the form, or false for FormNone.
+func (f NormalizationForm) step() (namedStep, bool) {
+	switch f {
+	case FormNFC:
+		return namedStep{"NFCNormalize", NFCNormalize}, true
+	case FormNFD:
+		return namedStep{"NFDNormalize", NFDNormalize}, true
+	case FormNFKC:
+		return namedStep{"NFKCNormalize", NFKCNormalize}, true
+	case FormNFKD:
+		return namedStep{"NFKDDecompose", NFKDDecompose}, true
+	default:
+		return namedStep{}, false
+	}
+}
+
 // RemoveControlChars removes Unicode control characters.
 func RemoveControlChars(s string) string {
 	var result strings.Builder
@@ -68,11 +262,174 @@ func RemoveControlChars(s string) string {
 	return result.String()
 }
 
+// removeASCIIControlChars is RemoveControlChars for ASCII input, returning
+// s without allocating when it has no control characters.
+func removeASCIIControlChars(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == 0x7f {
+			return RemoveControlChars(s)
+		}
+	}
+	return s
+}
+
+// RemoveFormatChars removes invisible Unicode format characters (category Cf)
+// such as the soft hyphen (U+00AD), zero-width space (U+200B) and zero-width
+// joiner (U+200D), which text copied from PDFs and websites often contains.
+func RemoveFormatChars(s string) string {
+	if strings.IndexFunc(s, isFormatChar) < 0 {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isFormatChar(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isFormatChar reports whether r is removed by RemoveFormatChars.
+func isFormatChar(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
+
+// CollapseRepeats shortens runs of three or more identical letters to two,
+// so elongations like "neiiiin" and "süüüß" become "neiin" and "süüß".
+// Legitimate German doubles ("Kaffee", "Schiff") are left alone, but triples
+// from compound joins ("Schifffahrt") are shortened as well. Letters are
+// compared case-insensitively; digits and punctuation are never collapsed.
+func CollapseRepeats(s string) string {
+	var result strings.Builder
+	result.Grow(len(s))
+
+	var prev rune
+	run := 0
+	for _, r := range s {
+		if run > 0 && unicode.IsLetter(r) && unicode.ToLower(r) == unicode.ToLower(prev) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = r
+		if run <= 2 {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// DefaultConfusables maps common OCR misreadings in scanned German text to
+// the characters they usually stand for.
+var DefaultConfusables = map[string]string{
+	"rn": "m",
+	"vv": "w",
+	"0":  "o",
+	"1":  "l",
+	"I":  "l",
+}
+
+var defaultConfusableReplacer = newConfusableReplacer(DefaultConfusables)
+
+// NormalizeConfusables replaces every OCR confusable in s using
+// DefaultConfusables: "Ha1le" → "Halle". The replacement is unconditional
+// and also rewrites correct words ("Kern" → "Kem"); the tokenizer instead
+// tries corrections only for words that miss the dictionary.
+func NormalizeConfusables(s string) string {
+	return defaultConfusableReplacer.Replace(s)
+}
+
+// newConfusableReplacer builds a replacer for table, preferring longer keys.
+func newConfusableReplacer(table map[string]string) *strings.Replacer {
+	keys := confusableKeys(table)
+	oldnew := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		oldnew = append(oldnew, k, table[k])
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// confusableKeys returns the keys of table, longest first, then sorted.
+func confusableKeys(table map[string]string) []string {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// confusableSet is a confusables table prepared once for candidates.
+type confusableSet struct {
+	table    map[string]string
+	keys     []string          // Keys of table in confusableKeys order
+	replacer *strings.Replacer // Applies every replacement at once
+}
+
+// newConfusableSet sorts the keys of table and builds its replacer.
+func newConfusableSet(table map[string]string) *confusableSet {
+	return &confusableSet{
+		table:    table,
+		keys:     confusableKeys(table),
+		replacer: newConfusableReplacer(table),
+	}
+}
+
+// candidates returns the corrections of word to try in order: each single
+// replacement from the table, then all replacements at once. OCR errors are
+// usually isolated, so single fixes are tried first.
+func (cs *confusableSet) candidates(word string) []string {
+	var candidates []string
+	seen := map[string]bool{word: true}
+	add := func(c string) {
+		if !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+
+	for _, k := range cs.keys {
+		for i := 0; ; {
+			j := strings.Index(word[i:], k)
+			if j < 0 {
+				break
+			}
+			i += j
+			add(word[:i] + cs.table[k] + word[i+len(k):])
+			i += len(k)
+		}
+	}
+	if len(candidates) > 1 {
+		add(cs.replacer.Replace(word))
+	}
+	return candidates
+}
+
 // Lowercase converts to lowercase.
 func Lowercase(s string) string {
 	return strings.ToLower(s)
 }
 
+// germanLowercasers pools German lowercasing Casers, which are stateful and
+// can't be shared between goroutines.
+var germanLowercasers = sync.Pool{
+	New: func() any { return cases.Lower(language.German) },
+}
+
+// LowercaseGerman converts to lowercase using the German case mapping rules
+// of golang.org/x/text/cases instead of simple per-rune mapping, e.g. a
+// word-final capital sigma becomes "ς": "ΟΔΟΣ" → "οδος". German words
+// lowercase the same as with Lowercase.
+func LowercaseGerman(s string) string {
+	caser := germanLowercasers.Get().(cases.Caser)
+	defer germanLowercasers.Put(caser)
+	return caser.String(s)
+}
+
 // quoteReplacements maps fancy quotes to ASCII.
 var quoteReplacements = map[rune]rune{
 	'\u201E': '"',  // „ German opening quote
@@ -101,6 +458,123 @@ func NormalizeQuotes(s string) string {
 	return result.String()
 }
 
+// archaicReplacer maps archaic letters of historical German prints to their
+// modern forms. "ſz" is the older spelling of ß, so it is replaced before ſ.
+var archaicReplacer = strings.NewReplacer(
+	"ſz", "ß",
+	"\uFB05", "st", // ﬅ long s + t ligature
+	"\uFB06", "st", // ﬆ s + t ligature
+	"ſ", "s",
+	"\uA75B", "r", // ꝛ r rotunda
+	"a\u0364", "ä", // Superscript e over a vowel, the historical umlaut
+	"o\u0364", "ö",
+	"u\u0364", "ü",
+	"A\u0364", "Ä",
+	"O\u0364", "Ö",
+	"U\u0364", "Ü",
+)
+
+// ExpandArchaic replaces archaic characters found in transcribed Fraktur
+// and other historical German texts: long s (ſ→s, "ſz"→ß), the long s
+// ligatures (ﬅ, ﬆ→st), r rotunda (ꝛ→r) and the superscript e umlaut
+// (aͤ→ä). NFKD maps ſ to s too, but loses "ſz" and the umlauts.
+func ExpandArchaic(s string) string {
+	return archaicReplacer.Replace(s)
+}
+
+// historicalPairs maps spellings left behind by the old German
+// hyphenation rules to their modern forms. Before 1996 "ck" was hyphenated
+// as "k-k" ("Zuk-ker") and "tz" as "z-z" ("Kaz-ze"); when a digitized text
+// marks the break with a soft hyphen, removing it leaves "Zukker" and
+// "Kazze" behind.
+//
+//	kk → ck   Zukker → Zucker, Bakke → Backe
+//	zz → tz   Kazze → Katze, Sizzung → Sitzung
+//
+// A pair already preceded by its modern letter is a compound joint, not an
+// artifact: "ckk" in Rückkehr, "tzz" in Satzzeichen.
+var historicalPairs = map[byte]byte{'k': 'c', 'z': 't'}
+
+// historicalPrefixes and historicalInfixes identify words whose "kk" or "zz"
+// is genuine, mostly loanwords, matched against the lowercased word. The
+// lists cover common words, not every one.
+var (
+	historicalPrefixes = []string{"akk", "okk"} // Akkord, Akku, akkurat, okkult
+	historicalInfixes  = []string{
+		"mokka", "sakko", "makkaron", "marokk", "trekk",
+		"pizz", "skizz", "jazz", "razzi", "mezz", "puzzl", "nizz", "bruzz",
+	}
+)
+
+// NormalizeHistoricalSpelling maps the "kk" and "zz" spellings produced by
+// the old hyphenation rules back to "ck" and "tz", see historicalPairs.
+// Each word is handled on its own, and words with a genuine "kk" or "zz"
+// (Akkord, Pizza, Skizze) are left unchanged. The replacements keep the
+// byte length, so offsets into s stay valid.
+//
+// The rewrite is unconditional and also changes words such as Netzzugang
+// or Stakkato; the tokenizer instead tries it only for words that miss the
+// dictionary.
+func NormalizeHistoricalSpelling(s string) string {
+	lower := strings.ToLower(s)
+	if !strings.Contains(lower, "kk") && !strings.Contains(lower, "zz") {
+		return s
+	}
+	var result strings.Builder
+	result.Grow(len(s))
+	start := -1
+	for i, r := range s {
+		if unicode.IsLetter(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			result.WriteString(modernizeWord(s[start:i]))
+			start = -1
+		}
+		result.WriteString(s[i : i+utf8.RuneLen(r)])
+	}
+	if start >= 0 {
+		result.WriteString(modernizeWord(s[start:]))
+	}
+	return result.String()
+}
+
+// modernizeWord replaces the historical "kk" and "zz" pairs in word unless
+// it is a known word with a genuine "kk" or "zz". Pairs following "c" or
+// "t" are kept.
+func modernizeWord(word string) string {
+	lower := strings.ToLower(word)
+	for _, prefix := range historicalPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return word
+		}
+	}
+	for _, infix := range historicalInfixes {
+		if strings.Contains(lower, infix) {
+			return word
+		}
+	}
+	// Lowercasing may change byte lengths, so pairs are found in word
+	// itself, ignoring ASCII case
+	b := []byte(word)
+	for i := 0; i+1 < len(b); i++ {
+		first, ok := historicalPairs[b[i]|0x20]
+		if !ok || b[i+1]|0x20 != b[i]|0x20 {
+			continue
+		}
+		if i > 0 && b[i-1]|0x20 == first {
+			i++
+			continue
+		}
+		b[i] = first&^0x20 | b[i]&0x20 // Keep the case of the replaced letter
+		i++
+	}
+	return string(b)
+}
+
 // ExpandLigatures expands æ→ae, œ→oe.
 func ExpandLigatures(s string) string {
 	s = strings.ReplaceAll(s, "æ", "ae")
@@ -110,14 +584,54 @@ func ExpandLigatures(s string) string {
 	return s
 }
 
-// ConvertEszett converts ß to ss.
+// eszettReplacer converts both the lowercase ß and the capital ẞ (U+1E9E).
+var eszettReplacer = strings.NewReplacer("ß", "ss", "\u1E9E", "SS")
+
+// ConvertEszett converts ß to ss and capital ẞ to SS.
 // Critical: NFKD does not decompose ß, so explicit conversion is needed.
 func ConvertEszett(s string) string {
-	return strings.ReplaceAll(s, "ß", "ss")
+	return eszettReplacer.Replace(s)
+}
+
+// umlautDigraphs maps precomposed umlauts to their digraph spelling.
+var umlautDigraphs = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue",
+	'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue",
+}
+
+// digraphBases maps umlaut base letters to their digraph spelling when
+// followed by a combining diaeresis (U+0308) after NFKD decomposition.
+var digraphBases = map[rune]string{
+	'a': "ae", 'o': "oe", 'u': "ue",
+	'A': "Ae", 'O': "Oe", 'U': "Ue",
+}
+
+// UmlautToDigraph expands umlauts to digraphs: ä→ae, ö→oe, ü→ue.
+// Handles both precomposed umlauts and NFKD-decomposed base + U+0308,
+// so it works before or after NFKDDecompose.
+func UmlautToDigraph(s string) string {
+	runes := []rune(s)
+	var result strings.Builder
+	result.Grow(len(s))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if digraph, ok := umlautDigraphs[r]; ok {
+			result.WriteString(digraph)
+			continue
+		}
+		if digraph, ok := digraphBases[r]; ok && i+1 < len(runes) && runes[i+1] == '\u0308' {
+			result.WriteString(digraph)
+			i++
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
 }
 
 // RemoveCombiningMarks removes Unicode combining characters (category Mn).
-// Removes umlaut dots after NFKD decomposition.
+// Removes umlaut dots and accents after NFKD decomposition; see
+// RemoveUmlautMarks and RemoveAccentMarks to remove only one kind.
 func RemoveCombiningMarks(s string) string {
 	var result strings.Builder
 	result.Grow(len(s))
@@ -129,6 +643,53 @@ func RemoveCombiningMarks(s string) string {
 	return result.String()
 }
 
+// umlautMark is the combining diaeresis NFKD separates from ä, ö and ü.
+const umlautMark = '\u0308'
+
+// isUmlautMark reports whether mark, following the base letter base, is a
+// German umlaut rather than an accent. The diaeresis on other letters
+// ("naïve") is an accent.
+func isUmlautMark(base, mark rune) bool {
+	if mark != umlautMark {
+		return false
+	}
+	switch base {
+	case 'a', 'o', 'u', 'A', 'O', 'U':
+		return true
+	}
+	return false
+}
+
+// removeMarks removes the combining marks (category Mn) for which remove,
+// given the preceding base letter, returns true.
+func removeMarks(s string, remove func(base, mark rune) bool) string {
+	var result strings.Builder
+	result.Grow(len(s))
+	var base rune
+	for _, r := range s {
+		if !unicode.Is(unicode.Mn, r) {
+			base = r
+		} else if remove(base, r) {
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// RemoveUmlautMarks removes the umlaut dots from ä, ö and ü after NFKD
+// decomposition and keeps all other marks: "Wärme Café" → "Warme Café".
+func RemoveUmlautMarks(s string) string {
+	return removeMarks(s, isUmlautMark)
+}
+
+// RemoveAccentMarks removes combining marks other than umlaut dots after
+// NFKD decomposition, for loanwords: "Café Wärme" → "Cafe Wärme",
+// "naïve" → "naive".
+func RemoveAccentMarks(s string) string {
+	return removeMarks(s, func(base, mark rune) bool { return !isUmlautMark(base, mark) })
+}
+
 // StemGerman applies the German Snowball stemmer.
 func StemGerman(s string) string {
 	stemmed, err := snowball.Stem(s, "german", true)
@@ -137,3 +698,21 @@ func StemGerman(s string) string {
 	}
 	return stemmed
 }
+
+// LemmatizeGerman returns a step that maps inflected forms to their lemma
+// using table ("häuser" → "haus"), falling back to StemGerman for words
+// without an entry. Keys must match the form produced by the preceding
+// pipeline steps, e.g. "hauser" if umlauts are stripped first.
+func LemmatizeGerman(table map[string]string) NormalizerFunc {
+	return lemmatizeWith(table, StemGerman)
+}
+
+// lemmatizeWith looks words up in table and applies fallback to misses.
+func lemmatizeWith(table map[string]string, fallback NormalizerFunc) NormalizerFunc {
+	return func(s string) string {
+		if lemma, ok := table[s]; ok {
+			return lemma
+		}
+		return fallback(s)
+	}
+}
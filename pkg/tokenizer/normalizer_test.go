@@ -1,6 +1,9 @@
 package tokenizer
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -107,6 +110,8 @@ func TestConvertEszett(t *testing.T) {
 		{"groß", "gross"},
 		{"Fuß", "Fuss"},
 		{"ß", "ss"},
+		{"STRA\u1E9EE", "STRASSE"}, // Capital eszett
+		{"\u1E9E", "SS"},
 	}
 
 	for _, tt := range tests {
@@ -136,6 +141,63 @@ func TestRemoveCombiningMarks(t *testing.T) {
 	}
 }
 
+func TestRemoveUmlautAndAccentMarks(t *testing.T) {
+	tests := []struct {
+		input  string
+		umlaut string
+		accent string
+	}{
+		{"Wa\u0308rme", "Warme", "Wa\u0308rme"},
+		{"Cafe\u0301", "Cafe\u0301", "Cafe"},
+		// The diaeresis on i is an accent, not an umlaut
+		{"nai\u0308ve", "nai\u0308ve", "naive"},
+		{"U\u0308bergro\u0308\u00dfe Re\u0301sume\u0301", "Ubergro\u00dfe Re\u0301sume\u0301", "U\u0308bergro\u0308\u00dfe Resume"},
+	}
+
+	for _, tt := range tests {
+		if result := RemoveUmlautMarks(tt.input); result != tt.umlaut {
+			t.Errorf("RemoveUmlautMarks(%q) = %q, want %q", tt.input, result, tt.umlaut)
+		}
+		if result := RemoveAccentMarks(tt.input); result != tt.accent {
+			t.Errorf("RemoveAccentMarks(%q) = %q, want %q", tt.input, result, tt.accent)
+		}
+	}
+}
+
+func TestNormalizer_UmlautAndAccentMarks(t *testing.T) {
+	tests := []struct {
+		name           string
+		umlaut, accent bool
+		cafe, waerme   string
+	}{
+		{"umlaut marks only", true, false, "café", "warme"},
+		{"accent marks only", false, true, "cafe", "wärme"},
+		{"both", true, true, "cafe", "warme"},
+		{"neither", false, false, "café", "wärme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig().Normalizers
+			cfg.StemGerman = false
+			cfg.RemoveCombiningMarks = false
+			cfg.RemoveUmlautMarks = tt.umlaut
+			cfg.RemoveAccentMarks = tt.accent
+			norm, err := cfg.buildNormalizer()
+			if err != nil {
+				t.Fatalf("buildNormalizer() error: %v", err)
+			}
+			// Compare composed, as NFKD leaves marks separated
+			if result := NFCNormalize(norm.Normalize("Café")); result != tt.cafe {
+				t.Errorf("Normalize(%q) = %q, want %q", "Café", result, tt.cafe)
+			}
+			if result := NFCNormalize(norm.Normalize("Wärme")); result != tt.waerme {
+				t.Errorf("Normalize(%q) = %q, want %q", "Wärme", result, tt.waerme)
+			}
+		})
+	}
+}
+
 func TestStemGerman(t *testing.T) {
 	// Note: Snowball stemmer behavior varies - just test it doesn't crash
 	tests := []string{
@@ -219,6 +281,28 @@ func TestNewNormalizerWithSteps(t *testing.T) {
 	}
 }
 
+func TestNewNormalizerWithSteps_NilStep(t *testing.T) {
+	n, err := NewNormalizerWithStepsSafe(Lowercase, nil, ConvertEszett)
+	if !errors.Is(err, ErrNilStep) || n != nil {
+		t.Fatalf("NewNormalizerWithStepsSafe(Lowercase, nil, ConvertEszett) = %v, %v, want ErrNilStep", n, err)
+	}
+	if !strings.Contains(err.Error(), "step 1") {
+		t.Errorf("error %q doesn't name the nil step's index", err)
+	}
+
+	if n, err := NewNormalizerWithStepsSafe(Lowercase); err != nil || n.Normalize("HAUS") != "haus" {
+		t.Errorf("NewNormalizerWithStepsSafe(Lowercase) = %v, %v", n, err)
+	}
+
+	defer func() {
+		r := recover()
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, ErrNilStep.Error()) {
+			t.Errorf("NewNormalizerWithSteps(nil) panicked with %v, want a message naming the nil step", r)
+		}
+	}()
+	NewNormalizerWithSteps(nil)
+}
+
 func TestFullPipelineUmlautHandling(t *testing.T) {
 	n := NewNormalizer()
 
@@ -247,4 +331,536 @@ func TestFullPipelineEszettHandling(t *testing.T) {
 	if result != "gross" {
 		t.Errorf("Full pipeline 'groß' = %q, want 'gross'", result)
 	}
+
+	// Capital ẞ in all-caps text
+	result = n.Normalize("STRA\u1E9EE")
+	if result != "strasse" {
+		t.Errorf("Full pipeline 'STRAẞE' = %q, want 'strasse'", result)
+	}
+
+	// Capital ẞ without a preceding Lowercase step
+	result = NewNormalizerWithSteps(ConvertEszett, Lowercase).Normalize("STRA\u1E9EE")
+	if result != "strasse" {
+		t.Errorf("ConvertEszett+Lowercase 'STRAẞE' = %q, want 'strasse'", result)
+	}
+}
+
+func TestUmlautToDigraph(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"wärme", "waerme"},
+		{"größe", "groeße"},
+		{"Übung", "Uebung"},
+		{"wa\u0308rme", "waerme"}, // ä decomposed
+		{"gro\u0308sse", "groesse"},
+		{"haus", "haus"},
+	}
+
+	for _, tt := range tests {
+		result := UmlautToDigraph(tt.input)
+		if result != tt.expected {
+			t.Errorf("UmlautToDigraph(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFullPipelineUmlautToDigraph(t *testing.T) {
+	n, err := NormalizerConfig{
+		NFKDDecompose:   true,
+		Lowercase:       true,
+		ConvertEszett:   true,
+		UmlautToDigraph: true,
+	}.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Wärme", "waerme"},
+		{"Größe", "groesse"},
+	}
+
+	for _, tt := range tests {
+		result := n.Normalize(tt.input)
+		if result != tt.expected {
+			t.Errorf("Digraph pipeline Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizer_NormalizeTrace(t *testing.T) {
+	n := NewNormalizer()
+
+	trace := n.NormalizeTrace("Größe")
+	if len(trace) != len(n.steps) {
+		t.Fatalf("NormalizeTrace returned %d steps, want %d", len(trace), len(n.steps))
+	}
+
+	expectedNames := []string{
+		"NFKDDecompose", "RemoveControlChars", "Lowercase", "NormalizeQuotes",
+		"ExpandLigatures", "ConvertEszett", "RemoveCombiningMarks", "StemGerman",
+	}
+	for i, name := range expectedNames {
+		if trace[i].Name != name {
+			t.Errorf("trace[%d].Name = %q, want %q", i, trace[i].Name, name)
+		}
+	}
+
+	if last := trace[len(trace)-1].Output; last != n.Normalize("Größe") {
+		t.Errorf("Final trace output = %q, want %q", last, n.Normalize("Größe"))
+	}
+}
+
+func TestNormalizerConfig_NormalizeTrace(t *testing.T) {
+	n, err := NormalizerConfig{
+		Lowercase:     true,
+		ConvertEszett: true,
+		StemGerman:    true,
+		MinStemLength: 4,
+		Custom:        map[string]NormalizerFunc{"noop": func(s string) string { return s }},
+		CustomOrder:   []string{"noop"},
+	}.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	trace := n.NormalizeTrace("Straße")
+	expectedNames := []string{"Lowercase", "ConvertEszett", "noop", "StemGerman"}
+	if len(trace) != len(expectedNames) {
+		t.Fatalf("NormalizeTrace returned %d steps, want %d", len(trace), len(expectedNames))
+	}
+	for i, name := range expectedNames {
+		if trace[i].Name != name {
+			t.Errorf("trace[%d].Name = %q, want %q", i, trace[i].Name, name)
+		}
+	}
+	if trace[1].Output != "strasse" {
+		t.Errorf("trace[1].Output = %q, want %q", trace[1].Output, "strasse")
+	}
+}
+
+func TestLemmatizeGerman(t *testing.T) {
+	lemmatize := LemmatizeGerman(map[string]string{
+		"häuser": "haus",
+		"männer": "mann",
+		"bücher": "buch",
+		"mütter": "mutter",
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"häuser", "haus"},
+		{"männer", "mann"},
+		{"bücher", "buch"},
+		{"mütter", "mutter"},
+		{"beton", StemGerman("beton")}, // Not in table: Snowball fallback
+	}
+
+	for _, tt := range tests {
+		result := lemmatize(tt.input)
+		if result != tt.expected {
+			t.Errorf("LemmatizeGerman(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizerConfig_Lemmatize(t *testing.T) {
+	n, err := NormalizerConfig{
+		NFKDDecompose:        true,
+		Lowercase:            true,
+		RemoveCombiningMarks: true,
+		StemGerman:           true,
+		Stemmer:              func(s string) string { return strings.TrimSuffix(s, "en") },
+		Lemmatize:            true,
+		Lemmas:               map[string]string{"hauser": "haus", "manner": "mann"},
+	}.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Häuser", "haus"},
+		{"Männer", "mann"},
+		{"Arbeiten", "arbeit"}, // Falls back to the configured stemmer
+	}
+
+	for _, tt := range tests {
+		result := n.Normalize(tt.input)
+		if result != tt.expected {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	trace := n.NormalizeTrace("Häuser")
+	if name := trace[len(trace)-1].Name; name != "LemmatizeGerman" {
+		t.Errorf("Last step = %q, want %q", name, "LemmatizeGerman")
+	}
+}
+
+func TestRemoveFormatChars(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Wär\u00ADmedämmung", "Wärmedämmung"}, // Soft hyphen
+		{"Brand\u200Bschutz", "Brandschutz"},   // Zero-width space
+		{"a\u200Db\u200Cc", "abc"},             // Zero-width joiner and non-joiner
+		{"\uFEFFHaus", "Haus"},                 // Byte order mark
+		{"Haus und Hof", "Haus und Hof"},
+	}
+
+	for _, tt := range tests {
+		result := RemoveFormatChars(tt.input)
+		if result != tt.expected {
+			t.Errorf("RemoveFormatChars(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"neiiiin", "neiin"},
+		{"süüüß", "süüß"},
+		{"JAAAaaa", "JAA"},
+		{"Kaffee", "Kaffee"},
+		{"Schifffahrt", "Schiffahrt"}, // Reform-spelling triples are shortened too
+		{"1000", "1000"},
+		{"!!!", "!!!"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		result := CollapseRepeats(tt.input)
+		if result != tt.expected {
+			t.Errorf("CollapseRepeats(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizer_CollapseRepeats(t *testing.T) {
+	cfg := NormalizerConfig{
+		CollapseRepeats:      true,
+		NFKDDecompose:        true,
+		Lowercase:            true,
+		RemoveCombiningMarks: true,
+	}
+	n, err := cfg.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	if result := n.Normalize("Süüüüß"); result != "suuß" {
+		t.Errorf("Normalize(%q) = %q, want %q", "Süüüüß", result, "suuß")
+	}
+}
+
+func TestNormalizeConfusables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Ha1le", "Halle"},
+		{"K0nzept", "Konzept"},
+		{"Därnmung", "Dämmung"},
+		{"Kern", "Kem"}, // Unconditional: correct words are rewritten too
+		{"haus", "haus"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		result := NormalizeConfusables(tt.input)
+		if result != tt.expected {
+			t.Errorf("NormalizeConfusables(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestConfusableSet_Candidates(t *testing.T) {
+	set := newConfusableSet(map[string]string{"rn": "m", "0": "o"})
+	result := set.candidates("Sternw0rn")
+	expected := []string{"Stemw0rn", "Sternw0m", "Sternworn", "Stemwom"}
+	if strings.Join(result, "|") != strings.Join(expected, "|") {
+		t.Errorf("candidates(%q) = %v, want %v", "Sternw0rn", result, expected)
+	}
+	if result := set.candidates("haus"); len(result) != 0 {
+		t.Errorf("candidates(%q) = %v, want none", "haus", result)
+	}
+}
+
+func TestNormalizationForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       NormalizerFunc
+		input    string
+		expected string
+	}{
+		// Compatibility characters: only the K forms replace them
+		{"NFKC", NFKCNormalize, "②", "2"},
+		{"NFKD", NFKDDecompose, "②", "2"},
+		{"NFC", NFCNormalize, "②", "②"},
+		{"NFD", NFDNormalize, "②", "②"},
+		{"NFKC", NFKCNormalize, "ﬁ", "fi"},
+		// Umlauts: only the D forms decompose them
+		{"NFKC", NFKCNormalize, "ä", "ä"},
+		{"NFKD", NFKDDecompose, "ä", "ä"},
+		{"NFC", NFCNormalize, "ä", "ä"},
+		{"NFD", NFDNormalize, "ä", "ä"},
+	}
+
+	for _, tt := range tests {
+		result := tt.fn(tt.input)
+		if result != tt.expected {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizer_NormalizationForm(t *testing.T) {
+	cfg := NormalizerConfig{NormalizationForm: FormNFKC, Lowercase: true}
+	norm, err := cfg.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+	// NFKC replaces ② but keeps ü precomposed
+	if result := norm.Normalize("Stück②"); result != "stück2" {
+		t.Errorf("Normalize(%q) = %q, want %q", "Stück②", result, "stück2")
+	}
+
+	loaded, err := LoadConfig(strings.NewReader(`{"normalizers": {"normalization_form": "nfkc"}}`))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+	if loaded.Normalizers.NormalizationForm != FormNFKC {
+		t.Errorf("LoadConfig normalization_form = %v, want FormNFKC", loaded.Normalizers.NormalizationForm)
+	}
+
+	bad := Config{Normalizers: NormalizerConfig{NFKDDecompose: true, NormalizationForm: FormNFC}}
+	if err := bad.Validate(); err == nil {
+		t.Error("Expected error for NFKDDecompose combined with NormalizationForm")
+	}
+}
+
+func TestLowercaseGerman(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// German words lowercase as with strings.ToLower
+		{"Wärme", "wärme"},
+		{"ÜBER", "über"},
+		{"STRASSE", "strasse"},
+		{"GROẞE", "große"},
+		{"Maße", "maße"},
+		// Word-final capital sigma becomes final sigma
+		{"ΟΔΟΣ", "οδος"},
+		{"ΣΑΣ ΣΑΣ", "σας σας"},
+		// Dotted capital I keeps its dot as a combining mark
+		{"İstanbul", "i̇stanbul"},
+	}
+
+	for _, tt := range tests {
+		result := LowercaseGerman(tt.input)
+		if result != tt.expected {
+			t.Errorf("LowercaseGerman(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// strings.ToLower maps these rune by rune
+	for _, input := range []string{"ΟΔΟΣ", "İstanbul"} {
+		if LowercaseGerman(input) == strings.ToLower(input) {
+			t.Errorf("LowercaseGerman(%q) = strings.ToLower, want German case rules", input)
+		}
+	}
+}
+
+func TestNormalizerConfig_LowercaseGerman(t *testing.T) {
+	cfg := NormalizerConfig{Lowercase: true, LowercaseGerman: true}
+	norm, err := cfg.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	// LowercaseGerman replaces the Lowercase step
+	trace := norm.NormalizeTrace("ΟΔΟΣ")
+	if len(trace) != 1 || trace[0].Name != "LowercaseGerman" {
+		t.Fatalf("NormalizeTrace steps = %v, want only LowercaseGerman", trace)
+	}
+	if result := norm.Normalize("Straßenbahn ΟΔΟΣ"); result != "straßenbahn οδος" {
+		t.Errorf("Normalize(%q) = %q, want %q", "Straßenbahn ΟΔΟΣ", result, "straßenbahn οδος")
+	}
+}
+
+func TestExpandArchaic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Waſſer", "Wasser"},
+		{"groſz", "groß"},
+		{"Geﬅalt", "Gestalt"},
+		{"Feﬆ", "Fest"},
+		{"Heꝛz", "Herz"},
+		{"Maͤdchen", "Mädchen"},
+		{"Oͤl", "Öl"},
+		{"Wasser", "Wasser"},
+	}
+
+	for _, tt := range tests {
+		result := ExpandArchaic(tt.input)
+		if result != tt.expected {
+			t.Errorf("ExpandArchaic(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizer_ExpandArchaic(t *testing.T) {
+	cfg := DefaultConfig().Normalizers
+	cfg.StemGerman = false
+	cfg.ExpandArchaic = true
+	norm, err := cfg.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Waſſer", "wasser"},
+		{"Schloſz", "schloss"},
+		{"Muͤhle", "muhle"},
+	}
+
+	for _, tt := range tests {
+		result := norm.Normalize(tt.input)
+		if result != tt.expected {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeHistoricalSpelling(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Zukker", "Zucker"},
+		{"ZUKKER", "ZUCKER"},
+		{"Kazze", "Katze"},
+		{"Zukker und Kazze", "Zucker und Katze"},
+		{"Akkord", "Akkord"},
+		{"Pizza", "Pizza"},
+		{"Skizze", "Skizze"},
+		{"Zukker mit Mokka", "Zucker mit Mokka"},
+		{"Zucker", "Zucker"},
+		{"Rückkehr", "Rückkehr"},
+		{"Satzzeichen", "Satzzeichen"},
+		{"SATZZEICHEN", "SATZZEICHEN"},
+		{"Blickkontakt", "Blickkontakt"},
+	}
+
+	for _, tt := range tests {
+		result := NormalizeHistoricalSpelling(tt.input)
+		if result != tt.expected {
+			t.Errorf("NormalizeHistoricalSpelling(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_NormalizeHistoricalSpelling(t *testing.T) {
+	cfg := testConfig()
+	cfg.Normalizers.RemoveFormatChars = true
+	cfg.Normalizers.NormalizeHistoricalSpelling = true
+	dictPath := newTestDictionary(t, "zucker", "dose", "rück", "kehr", "satz", "zeichen", "schutt", "schutz", "zone")
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// "Zuk-ker" with a soft hyphen, as in texts set before the 1996 reform
+		{"Zuk\u00ADkerdose", []string{"zuckerdose", "zucker", "dose"}},
+		// Modern compounds joined at ck+k or tz+z
+		{"Rückkehr", []string{"rückkehr", "ruck", "kehr"}},
+		{"Satzzeichen", []string{"satzzeichen", "satz", "zeichen"}},
+		{"Schutzzone", []string{"schutzzone", "schutz", "zone"}},
+		// Unknown words are kept when the modern form doesn't resolve either
+		{"Mozzarella", []string{"mozzarella"}},
+		{"Stakkato", []string{"stakkato"}},
+	}
+
+	for _, tt := range tests {
+		result := tok.Tokenize(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizer_ASCIIFastPath(t *testing.T) {
+	// applyAll runs every step, bypassing the fast path
+	applyAll := func(n *Normalizer, s string) string {
+		for _, step := range n.steps {
+			s = step(s)
+		}
+		return s
+	}
+
+	full := DefaultConfig().Normalizers
+	full.RemoveFormatChars = true
+	full.ExpandArchaic = true
+	full.NormalizeHistoricalSpelling = true
+	german := full
+	german.LowercaseGerman = true
+	digraph := full
+	digraph.RemoveCombiningMarks, digraph.UmlautToDigraph = false, true
+	custom := full
+	custom.Custom = map[string]NormalizerFunc{
+		"umlaut": func(s string) string { return strings.ReplaceAll(s, "ae", "ä") },
+	}
+	custom.CustomOrder = []string{"umlaut"}
+	custom.CustomPosition = CustomFirst
+
+	inputs := []string{
+		"Haus", "HAUS", "Brandschutz", "haus\tbau\x7f", "Strasse", "Baeume", "",
+		"Wärme", "Straße", "Æther", "Waſſer", "Zukker",
+	}
+
+	for _, cfg := range []NormalizerConfig{full, german, digraph, custom} {
+		norm, err := cfg.buildNormalizer()
+		if err != nil {
+			t.Fatalf("buildNormalizer() error: %v", err)
+		}
+		for _, input := range inputs {
+			if result, expected := norm.Normalize(input), applyAll(norm, input); result != expected {
+				t.Errorf("Normalize(%q) = %q, want %q as without the ASCII fast path", input, result, expected)
+			}
+		}
+	}
+
+	// Steps that leave ASCII alone are skipped, unknown ones are not
+	norm := NewNormalizerWithSteps(NFKDDecompose, Lowercase, strings.ToUpper)
+	if !norm.ascii[0].known || norm.ascii[0].fn != nil {
+		t.Error("Expected NFKDDecompose to be skipped for ASCII input")
+	}
+	if norm.ascii[2].known {
+		t.Error("Expected an unknown step to always run")
+	}
 }
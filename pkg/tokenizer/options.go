@@ -0,0 +1,237 @@
+package tokenizer
+
+import "log/slog"
+
+// Option overrides a single field of the default Config.
+type Option func(*Config)
+
+// DefaultConfig returns the full search-indexing configuration: cache enabled,
+// lowercase original emitted and every built-in normalizer except
+// UmlautToDigraph enabled.
+func DefaultConfig() Config {
+	return Config{
+		Cache:             true,
+		CacheSize:         CacheSize,
+		LowercaseOriginal: true,
+		EmitVariants:      false,
+		MaxWordLength:     DefaultMaxWordLength,
+		Normalizers: NormalizerConfig{
+			NFKDDecompose:        true,
+			RemoveControlChars:   true,
+			RemoveFormatChars:    true,
+			Lowercase:            true,
+			NormalizeQuotes:      true,
+			ExpandLigatures:      true,
+			ConvertEszett:        true,
+			UmlautToDigraph:      false,
+			RemoveCombiningMarks: true,
+			StemGerman:           true,
+		},
+	}
+}
+
+// NewTokenizerWithOptions creates a tokenizer from DefaultConfig with the
+// given options applied in order.
+//
+// Example usage:
+//
+//	tok, _ := NewTokenizerWithOptions(dictPath,
+//	    WithCache(false),
+//	    WithStemming(false),
+//	)
+func NewTokenizerWithOptions(dictPath string, opts ...Option) (*Tokenizer, error) {
+	cfg := DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewTokenizer(dictPath, cfg)
+}
+
+// WithCache enables or disables the compound split cache. Enabling it
+// without a size set uses the default CacheSize.
+func WithCache(enabled bool) Option {
+	return func(c *Config) {
+		c.Cache = enabled
+		if enabled && c.CacheSize == 0 {
+			c.CacheSize = CacheSize
+		}
+	}
+}
+
+// WithCacheSize enables the split cache with room for size entries.
+// A size of 0 disables caching.
+func WithCacheSize(size int) Option {
+	return func(c *Config) {
+		c.Cache = size > 0
+		c.CacheSize = size
+	}
+}
+
+// WithLowercaseOriginal enables or disables emitting the lowercase original.
+func WithLowercaseOriginal(enabled bool) Option {
+	return func(c *Config) { c.LowercaseOriginal = enabled }
+}
+
+// WithEmitVariants enables or disables umlaut spelling variants.
+func WithEmitVariants(enabled bool) Option {
+	return func(c *Config) { c.EmitVariants = enabled }
+}
+
+// WithCaseTag enables or disables case tags in TokenizeTagged.
+func WithCaseTag(enabled bool) Option {
+	return func(c *Config) { c.EmitCaseTag = enabled }
+}
+
+// WithEszettVariants enables or disables emitting both ß and ss forms.
+func WithEszettVariants(enabled bool) Option {
+	return func(c *Config) { c.EmitEszettVariants = enabled }
+}
+
+// WithMaxWordLength sets the longest word (in runes) considered for decomposition.
+func WithMaxWordLength(n int) Option {
+	return func(c *Config) { c.MaxWordLength = n }
+}
+
+// WithMaxSegments rejects decompositions with more than n segments (0 = unlimited).
+func WithMaxSegments(n int) Option {
+	return func(c *Config) { c.MaxSegments = n }
+}
+
+// WithMaxLookups leaves words unsplit once splitting them needs more than n
+// dictionary candidate checks (0 = unlimited).
+func WithMaxLookups(n int) Option {
+	return func(c *Config) { c.MaxLookups = n }
+}
+
+// WithPrefixFallback enables or disables retrying words that don't split
+// with a prefix from GermanPrefixes stripped.
+func WithPrefixFallback(enabled bool) Option {
+	return func(c *Config) { c.PrefixFallback = enabled }
+}
+
+// WithSplitThreshold keeps compounds whose split confidence is below
+// threshold whole instead of emitting their segments (0 = disabled).
+func WithSplitThreshold(threshold float64) Option {
+	return func(c *Config) { c.SplitThreshold = threshold }
+}
+
+// WithRecursiveSplit enables or disables decomposing segments that are
+// themselves compounds.
+func WithRecursiveSplit(enabled bool) Option {
+	return func(c *Config) { c.RecursiveSplit = enabled }
+}
+
+// WithDigitBoundaries enables or disables splitting words at letter/digit
+// transitions before compound decomposition.
+func WithDigitBoundaries(enabled bool) Option {
+	return func(c *Config) { c.DigitBoundaries = enabled }
+}
+
+// WithPassThroughNonLatin enables or disables lowercase-only handling of
+// words written in a non-Latin script.
+func WithPassThroughNonLatin(enabled bool) Option {
+	return func(c *Config) { c.PassThroughNonLatin = enabled }
+}
+
+// WithExpandElisions enables or disables expanding "Ein- und Ausgang" to
+// "Eingang und Ausgang".
+func WithExpandElisions(enabled bool) Option {
+	return func(c *Config) { c.ExpandElisions = enabled }
+}
+
+// WithJoinersInWords enables or disables keeping zero-width joiners inside
+// words: "Auf\u200clage" → "auflage".
+func WithJoinersInWords(enabled bool) Option {
+	return func(c *Config) { c.JoinersInWords = enabled }
+}
+
+// WithLogger sends dictionary events and unsplit words to logger (nil = silent).
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) { c.Logger = logger }
+}
+
+// WithMetrics sends token counts, split timings and fallbacks to m (nil = off).
+func WithMetrics(m Metrics) Option {
+	return func(c *Config) { c.Metrics = m }
+}
+
+// WithStopWords drops the given lowercase words from the output (nil keeps all).
+func WithStopWords(words map[string]struct{}) Option {
+	return func(c *Config) { c.StopWords = words }
+}
+
+// WithAlwaysEmitWhole enables or disables emitting the normalized whole
+// compound in addition to its segments.
+func WithAlwaysEmitWhole(enabled bool) Option {
+	return func(c *Config) { c.AlwaysEmitWhole = enabled }
+}
+
+// WithDedupAcrossForms enables or disables dropping the lowercase original
+// of words whose normalized form is also emitted.
+func WithDedupAcrossForms(enabled bool) Option {
+	return func(c *Config) { c.DedupAcrossForms = enabled }
+}
+
+// WithTokenFilter post-processes output tokens with filter (nil keeps all).
+func WithTokenFilter(filter TokenFilter) Option {
+	return func(c *Config) { c.TokenFilter = filter }
+}
+
+// WithTrieBackend enables or disables the in-memory trie split backend.
+func WithTrieBackend(enabled bool) Option {
+	return func(c *Config) { c.TrieBackend = enabled }
+}
+
+// WithBloomFilter enables or disables the dictionary bloom filter.
+func WithBloomFilter(enabled bool) Option {
+	return func(c *Config) { c.BloomFilter = enabled }
+}
+
+// WithBatchWorkers sets the number of goroutines used by TokenizeBatch (0 = GOMAXPROCS).
+func WithBatchWorkers(n int) Option {
+	return func(c *Config) { c.BatchWorkers = n }
+}
+
+// WithStemming enables or disables the stemming step.
+func WithStemming(enabled bool) Option {
+	return func(c *Config) { c.Normalizers.StemGerman = enabled }
+}
+
+// WithStemmer replaces the stemming step and skips words shorter than minLength runes.
+func WithStemmer(stem NormalizerFunc, minLength int) Option {
+	return func(c *Config) {
+		c.Normalizers.Stemmer = stem
+		c.Normalizers.MinStemLength = minLength
+	}
+}
+
+// WithStemNounsOnly enables or disables restricting stemming to noun-like words.
+func WithStemNounsOnly(enabled bool) Option {
+	return func(c *Config) { c.StemNounsOnly = enabled }
+}
+
+// WithOutputCase sets the casing of emitted tokens.
+func WithOutputCase(mode OutputCase) Option {
+	return func(c *Config) { c.OutputCase = mode }
+}
+
+// WithSegmentNormalization sets how the segments of a word are emitted.
+func WithSegmentNormalization(mode SegmentNormalization) Option {
+	return func(c *Config) { c.SegmentNormalization = mode }
+}
+
+// WithNormalizers replaces the whole normalizer configuration.
+func WithNormalizers(nc NormalizerConfig) Option {
+	return func(c *Config) { c.Normalizers = nc }
+}
+
+// WithCustomStep registers a named custom normalizer step and appends it to CustomOrder.
+func WithCustomStep(name string, step NormalizerFunc) Option {
+	return func(c *Config) {
+		if c.Normalizers.Custom == nil {
+			c.Normalizers.Custom = make(map[string]NormalizerFunc)
+		}
+		c.Normalizers.Custom[name] = step
+		c.Normalizers.CustomOrder = append(c.Normalizers.CustomOrder, name)
+	}
+}
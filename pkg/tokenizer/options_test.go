@@ -0,0 +1,115 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTokenizerWithOptions_Defaults(t *testing.T) {
+	tok, err := NewTokenizerWithOptions(getTestDictPath())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	if !tok.CacheEnabled() {
+		t.Error("Expected cache to be enabled by default")
+	}
+	if !tok.LowercaseOriginalEnabled() {
+		t.Error("Expected lowercase original to be enabled by default")
+	}
+}
+
+func TestNewTokenizerWithOptions_Compose(t *testing.T) {
+	tok, err := NewTokenizerWithOptions(getTestDictPath(),
+		WithCache(false),
+		WithLowercaseOriginal(false),
+		WithCustomStep("ph", func(s string) string { return strings.ReplaceAll(s, "ph", "f") }),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	if tok.CacheEnabled() {
+		t.Error("Expected WithCache(false) to disable cache")
+	}
+	if tok.LowercaseOriginalEnabled() {
+		t.Error("Expected WithLowercaseOriginal(false) to disable lowercase original")
+	}
+
+	// Unspecified normalizers keep their defaults: umlauts stripped, ß converted
+	result := tok.Tokenize("Größe")
+	if len(result) != 1 || result[0] != "grosse" {
+		t.Errorf("Tokenize(%q) = %v, want [grosse]", "Größe", result)
+	}
+
+	result = tok.Tokenize("Philosophie")
+	if len(result) != 1 || result[0] != "filosofie" {
+		t.Errorf("Tokenize(%q) = %v, want [filosofie]", "Philosophie", result)
+	}
+}
+
+func TestNewTokenizerWithOptions_LastWins(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, opt := range []Option{WithStemming(false), WithStemming(true), WithCache(false)} {
+		opt(&cfg)
+	}
+
+	if !cfg.Normalizers.StemGerman {
+		t.Error("Expected later WithStemming(true) to override earlier option")
+	}
+	if cfg.Cache {
+		t.Error("Expected WithCache(false) to apply")
+	}
+	if !cfg.Normalizers.NFKDDecompose {
+		t.Error("Expected unrelated defaults to be kept")
+	}
+}
+
+func TestWithCacheSize(t *testing.T) {
+	cfg := DefaultConfig()
+	WithCacheSize(500)(&cfg)
+	if !cfg.Cache || cfg.CacheSize != 500 {
+		t.Errorf("WithCacheSize(500) = {Cache: %v, CacheSize: %d}, want {true 500}", cfg.Cache, cfg.CacheSize)
+	}
+
+	WithCacheSize(0)(&cfg)
+	if cfg.Cache {
+		t.Error("Expected WithCacheSize(0) to disable cache")
+	}
+}
+
+func TestNewTokenizer_CacheSizeZeroDisablesCache(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.CacheSize = 0
+	tests := []struct {
+		name    string
+		newTok  func() (*Tokenizer, error)
+		enabled bool
+	}{
+		{"Config.CacheSize 0", func() (*Tokenizer, error) { return NewTokenizer(dictPath, cfg) }, false},
+		{"WithCacheSize(0)", func() (*Tokenizer, error) { return NewTokenizerWithOptions(dictPath, WithCacheSize(0)) }, false},
+		{"default config", func() (*Tokenizer, error) { return NewTokenizerWithOptions(dictPath) }, true},
+		{"WithCache(true) after WithCacheSize(0)", func() (*Tokenizer, error) {
+			return NewTokenizerWithOptions(dictPath, WithCacheSize(0), WithCache(true))
+		}, true},
+	}
+
+	for _, tt := range tests {
+		tok, err := tt.newTok()
+		if err != nil {
+			t.Fatalf("%s: failed to create tokenizer: %v", tt.name, err)
+		}
+		tok.Tokenize("Brandschutzkonzept")
+		if got := tok.CacheEnabled(); got != tt.enabled {
+			t.Errorf("%s: CacheEnabled() = %v, want %v", tt.name, got, tt.enabled)
+		}
+		if !tt.enabled && tok.CacheSize() != 0 {
+			t.Errorf("%s: CacheSize() = %d, want 0", tt.name, tok.CacheSize())
+		}
+		tok.Close()
+	}
+}
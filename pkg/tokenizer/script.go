@@ -0,0 +1,18 @@
+package tokenizer
+
+import "unicode"
+
+// isMostlyLatin reports whether at least half of the letters in word are in
+// the Latin script. Words without letters, such as numbers, count as Latin.
+func isMostlyLatin(word string) bool {
+	latin, other := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.IsLetter(r):
+			other++
+		}
+	}
+	return latin >= other
+}
@@ -0,0 +1,25 @@
+package tokenizer
+
+import "testing"
+
+func TestIsMostlyLatin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"Wärme", true},
+		{"Москва", false},
+		{"ΑΘΗΝΑ", false},
+		{"東京", false},
+		{"1000", true},
+		{"iPhoneМ", true},
+		{"Мoskva", true},
+	}
+
+	for _, tt := range tests {
+		result := isMostlyLatin(tt.input)
+		if result != tt.expected {
+			t.Errorf("isMostlyLatin(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package tokenizer
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/vellum"
+	vregexp "github.com/blevesearch/vellum/regexp"
+)
+
+// globWildcards are the pattern characters Search treats specially.
+const globWildcards = "*?"
+
+// Search returns the sorted dictionary words matching pattern, where '*'
+// matches any run of characters and '?' any single character: "*schaft",
+// "brand*", "*schutz*". Matching is case-insensitive.
+//
+// Only the FST key range sharing the pattern's literal prefix is visited,
+// and the rest of the pattern is matched by an automaton during the FST
+// walk. Patterns too complex for the automaton fall back to iterating the
+// range. While there are unflushed changes, the word set is searched instead.
+// Nothing matches once the dictionary is closed.
+func (d *Dictionary) Search(pattern string) []string {
+	pattern = strings.ToLower(pattern)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return nil
+	}
+	if d.dirty {
+		var matches []string
+		for _, word := range d.sortedWords() {
+			if globMatch(pattern, word) {
+				matches = append(matches, word)
+			}
+		}
+		return matches
+	}
+
+	prefix := pattern
+	if i := strings.IndexAny(pattern, globWildcards); i >= 0 {
+		prefix = pattern[:i]
+	}
+	rest := pattern[len(prefix):]
+
+	switch rest {
+	case "":
+		// No wildcards: a plain lookup
+		if _, exists, _ := d.fst.Get([]byte(pattern)); exists {
+			return []string{pattern}
+		}
+		return nil
+	case "*":
+		// Prefix search: the key range alone decides
+		return collectKeys(d.fst.Iterator([]byte(prefix), prefixEnd(prefix)))
+	}
+
+	if aut, err := vregexp.New(globToRegexp(pattern)); err == nil {
+		return collectKeys(d.fst.Search(aut, []byte(prefix), prefixEnd(prefix)))
+	}
+
+	var matches []string
+	for _, word := range collectKeys(d.fst.Iterator([]byte(prefix), prefixEnd(prefix))) {
+		if globMatch(pattern, word) {
+			matches = append(matches, word)
+		}
+	}
+	return matches
+}
+
+// LongestPrefix returns the longest dictionary word that is a prefix of s,
+// e.g. "brandschutz" for "brandschutzkon" when both "brand" and
+// "brandschutz" are words. Matching is case-insensitive.
+//
+// The FST is walked along s byte by byte, so the whole search costs one
+// traversal rather than a lookup per candidate length, and stops as soon
+// as no key continues the input. While there are unflushed changes, the
+// word set is consulted instead. Nothing matches once the dictionary is
+// closed.
+func (d *Dictionary) LongestPrefix(s string) (match string, ok bool) {
+	lower := strings.ToLower(s)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return "", false
+	}
+	if d.dirty {
+		for end := len(lower); end > 0; {
+			if _, exists := d.words[lower[:end]]; exists {
+				return lower[:end], true
+			}
+			_, size := utf8.DecodeLastRuneInString(lower[:end])
+			end -= size
+		}
+		return "", false
+	}
+
+	longest := 0
+	addr := d.fst.Start()
+	for i := 0; i < len(lower); i++ {
+		if addr = d.fst.Accept(addr, lower[i]); !d.fst.CanMatch(addr) {
+			break
+		}
+		if d.fst.IsMatch(addr) {
+			longest = i + 1
+		}
+	}
+	if longest == 0 {
+		return "", false
+	}
+	return lower[:longest], true
+}
+
+// collectKeys drains an FST iterator into a slice of keys, which the FST
+// yields in sorted order.
+func collectKeys(it *vellum.FSTIterator, err error) []string {
+	var keys []string
+	for err == nil {
+		key, _ := it.Current()
+		keys = append(keys, string(key))
+		err = it.Next()
+	}
+	if !errors.Is(err, vellum.ErrIteratorDone) {
+		return nil
+	}
+	return keys
+}
+
+// prefixEnd returns the smallest key greater than every key starting with
+// prefix, or nil if there is none (empty prefix or all 0xff bytes).
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// globToRegexp translates a glob pattern into an equivalent regular
+// expression. Vellum regexps match whole keys, so no anchors are added.
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// globMatch reports whether s matches the glob pattern, where '*' matches
+// any run of runes and '?' a single rune.
+func globMatch(pattern, s string) bool {
+	p, w := []rune(pattern), []rune(s)
+	pi, wi := 0, 0
+	star, mark := -1, 0
+	for wi < len(w) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == w[wi]):
+			pi++
+			wi++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, wi
+			pi++
+		case star >= 0:
+			// Let the last '*' absorb one more rune and retry
+			mark++
+			pi, wi = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
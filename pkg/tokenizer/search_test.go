@@ -0,0 +1,131 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDictionary_Search(t *testing.T) {
+	dictPath := newTestDictionary(t,
+		"brand", "brandschutz", "schutz", "schutzhelm", "feuerschutzmittel",
+		"wissenschaft", "gemeinschaft", "schaft", "haus",
+	)
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		// Suffix
+		{"*schaft", []string{"gemeinschaft", "schaft", "wissenschaft"}},
+		// Prefix
+		{"brand*", []string{"brand", "brandschutz"}},
+		{"Schutz*", []string{"schutz", "schutzhelm"}},
+		// Infix
+		{"*schutz*", []string{"brandschutz", "feuerschutzmittel", "schutz", "schutzhelm"}},
+		// Single-rune wildcard
+		{"h?us", []string{"haus"}},
+		// Literal
+		{"haus", []string{"haus"}},
+		{"maus", nil},
+		{"*xyz*", nil},
+	}
+
+	for _, tt := range tests {
+		result := dict.Search(tt.pattern)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Search(%q) = %v, want %v", tt.pattern, result, tt.expected)
+		}
+	}
+
+	// Unflushed words are found too
+	if err := dict.AddWord("landwirtschaft"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	expected := []string{"gemeinschaft", "landwirtschaft", "schaft", "wissenschaft"}
+	if result := dict.Search("*schaft"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Search(%q) while dirty = %v, want %v", "*schaft", result, expected)
+	}
+
+	dict.Close()
+	if result := dict.Search("*schaft"); result != nil {
+		t.Errorf("Search(%q) after Close = %v, want none", "*schaft", result)
+	}
+}
+
+func TestDictionary_LongestPrefix(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "brandschutz", "b", "schutz", "über", "überall")
+	dict, err := NewDictionary(dictPath)
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	tests := []struct {
+		input string
+		match string
+		ok    bool
+	}{
+		// Several prefixes match; the longest wins
+		{"brandschutzkonzept", "brandschutz", true},
+		{"brandmauer", "brand", true},
+		{"bra", "b", true},
+		{"Brandschutz", "brandschutz", true},
+		{"überallhin", "überall", true},
+		{"überzug", "über", true},
+		// No match
+		{"haus", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		match, ok := dict.LongestPrefix(tt.input)
+		if match != tt.match || ok != tt.ok {
+			t.Errorf("LongestPrefix(%q) = %q, %v, want %q, %v", tt.input, match, ok, tt.match, tt.ok)
+		}
+	}
+
+	// Unflushed words are found too
+	if err := dict.AddWord("brandschutzkonzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+	if match, ok := dict.LongestPrefix("brandschutzkonzepte"); match != "brandschutzkonzept" || !ok {
+		t.Errorf("LongestPrefix() while dirty = %q, %v, want %q, true", match, ok, "brandschutzkonzept")
+	}
+	if match, ok := dict.LongestPrefix("überzug"); match != "über" || !ok {
+		t.Errorf("LongestPrefix() while dirty = %q, %v, want %q, true", match, ok, "über")
+	}
+
+	dict.Close()
+	if match, ok := dict.LongestPrefix("brandschutzkonzept"); match != "" || ok {
+		t.Errorf("LongestPrefix() after Close = %q, %v, want \"\", false", match, ok)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"*schaft", "wissenschaft", true},
+		{"*schaft", "schafts", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"h?us", "häus", true},
+		{"*", "", true},
+		{"", "", true},
+		{"?", "", false},
+	}
+
+	for _, tt := range tests {
+		result := globMatch(tt.pattern, tt.input)
+		if result != tt.expected {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.input, result, tt.expected)
+		}
+	}
+}
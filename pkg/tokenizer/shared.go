@@ -0,0 +1,99 @@
+package tokenizer
+
+import (
+	"log/slog"
+	"path/filepath"
+	"sync"
+)
+
+// sharedDictionary is a dictionary used by one or more tokenizers.
+type sharedDictionary struct {
+	once      sync.Once // Loads dict on first use
+	dict      *Dictionary
+	err       error
+	bloomOnce sync.Once // Enables the bloom filter for the first tokenizer asking for it
+	bloomErr  error
+	refs      int // Guarded by sharedDictionaries.mu
+}
+
+// sharedDictionaries caches the dictionaries of SharedTokenizer by absolute path.
+var sharedDictionaries = struct {
+	mu    sync.Mutex
+	dicts map[string]*sharedDictionary
+}{dicts: make(map[string]*sharedDictionary)}
+
+// SharedTokenizer creates a tokenizer like NewTokenizer, but shares one
+// Dictionary between all shared tokenizers for the same dictPath, so the FST
+// is loaded once however many goroutines construct tokenizers concurrently.
+// The dictionary is closed when the last tokenizer using it is closed.
+//
+// Everything but the dictionary is per tokenizer, including the split cache.
+// Words added through one tokenizer are seen by all of them, and the
+// dictionary logs through the Logger of the tokenizer that loaded it. If any
+// of them sets BloomFilter, the shared dictionary gets a bloom filter, which
+// leaves lookup results unchanged for the others.
+// ReloadDictionary gives a tokenizer a private dictionary of its own.
+func SharedTokenizer(dictPath string, cfg Config) (*Tokenizer, error) {
+	return newTokenizer(cfg, func(logger *slog.Logger) (*Dictionary, func() error, error) {
+		return acquireDictionary(dictPath, cfg.BloomFilter, logger)
+	})
+}
+
+// acquireDictionary returns the shared dictionary for path, loading it if
+// needed, along with the function that releases this reference. Releasing
+// more than once has no further effect. With bloom set, the bloom filter is
+// enabled on the shared dictionary if it isn't already.
+func acquireDictionary(path string, bloom bool, logger *slog.Logger) (*Dictionary, func() error, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	sharedDictionaries.mu.Lock()
+	shared, ok := sharedDictionaries.dicts[path]
+	if !ok {
+		shared = &sharedDictionary{}
+		sharedDictionaries.dicts[path] = shared
+	}
+	shared.refs++
+	sharedDictionaries.mu.Unlock()
+
+	// Load outside the registry lock so other paths are not blocked
+	shared.once.Do(func() {
+		shared.dict, shared.err = openDictionary(path, false, logger)
+	})
+
+	release := sync.OnceValue(func() error { return releaseDictionary(path, shared) })
+	if shared.err != nil {
+		release()
+		return nil, nil, shared.err
+	}
+	if bloom {
+		shared.bloomOnce.Do(func() {
+			shared.bloomErr = shared.dict.EnableBloomFilter(DefaultBloomFalsePositiveRate)
+		})
+		if shared.bloomErr != nil {
+			release()
+			return nil, nil, shared.bloomErr
+		}
+	}
+	return shared.dict, release, nil
+}
+
+// releaseDictionary drops a reference to shared, closing its dictionary and
+// removing it from the cache when none remain.
+func releaseDictionary(path string, shared *sharedDictionary) error {
+	sharedDictionaries.mu.Lock()
+	defer sharedDictionaries.mu.Unlock()
+
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+	if sharedDictionaries.dicts[path] == shared {
+		delete(sharedDictionaries.dicts, path)
+	}
+	if shared.dict == nil {
+		return nil
+	}
+	return shared.dict.Close()
+}
@@ -0,0 +1,154 @@
+package tokenizer
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSharedTokenizer_Concurrent(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept")
+	var logs bytes.Buffer
+	cfg := testConfig()
+	cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	const n = 32
+	toks := make([]*Tokenizer, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range toks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			toks[i], errs[i] = SharedTokenizer(dictPath, cfg)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("SharedTokenizer() #%d error: %v", i, err)
+		}
+	}
+	if loads := strings.Count(logs.String(), `msg="rebuilding FST"`); loads != 1 {
+		t.Errorf("FST built %d times, want 1", loads)
+	}
+	for i, tok := range toks {
+		if tok.dict != toks[0].dict {
+			t.Fatalf("Tokenizer #%d has its own dictionary, want the shared one", i)
+		}
+	}
+
+	// Closing all but one tokenizer keeps the dictionary open
+	for _, tok := range toks[1:] {
+		if err := tok.Close(); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+	}
+	expected := []string{"brandschutz", "brand", "schutz"}
+	if result := toks[0].Tokenize("Brandschutz"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) after closing others = %v, want %v", "Brandschutz", result, expected)
+	}
+
+	// Closing twice doesn't release another tokenizer's reference
+	if err := toks[1].Close(); err != nil {
+		t.Fatalf("second Close() error: %v", err)
+	}
+	if toks[0].dict.closed {
+		t.Fatal("Dictionary closed while still in use")
+	}
+
+	dict := toks[0].dict
+	if err := toks[0].Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if !dict.closed {
+		t.Error("Dictionary still open after the last tokenizer closed")
+	}
+
+	// The next shared tokenizer loads the dictionary afresh
+	tok, err := SharedTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("SharedTokenizer() error: %v", err)
+	}
+	defer tok.Close()
+	if tok.dict == dict {
+		t.Error("SharedTokenizer() reused a closed dictionary")
+	}
+}
+
+func TestSharedTokenizer_ReloadDictionary(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	a, err := SharedTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("SharedTokenizer() error: %v", err)
+	}
+	defer a.Close()
+	b, err := SharedTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("SharedTokenizer() error: %v", err)
+	}
+	defer b.Close()
+
+	shared := a.dict
+	if err := a.ReloadDictionary(newTestDictionary(t, "haus")); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+	if a.dict == shared {
+		t.Error("ReloadDictionary() kept the shared dictionary")
+	}
+	if shared.closed {
+		t.Error("ReloadDictionary() closed a dictionary still used by another tokenizer")
+	}
+	if result := b.Tokenize("Brandschutz"); len(result) != 3 {
+		t.Errorf("Tokenize(%q) on the other tokenizer = %v, want 3 tokens", "Brandschutz", result)
+	}
+}
+
+func TestSharedTokenizer_BloomFilter(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	plain, err := SharedTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("SharedTokenizer() error: %v", err)
+	}
+	defer plain.Close()
+	if plain.dict.bloom != nil {
+		t.Fatal("Shared dictionary has a bloom filter nobody asked for")
+	}
+
+	cfg := testConfig()
+	cfg.BloomFilter = true
+	bloom, err := SharedTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("SharedTokenizer() with BloomFilter error: %v", err)
+	}
+	defer bloom.Close()
+
+	if bloom.dict != plain.dict {
+		t.Fatal("BloomFilter tokenizer loaded its own dictionary, want the shared one")
+	}
+	if bloom.dict.bloom == nil {
+		t.Error("Shared dictionary has no bloom filter after a BloomFilter tokenizer joined")
+	}
+	for _, tok := range []*Tokenizer{plain, bloom} {
+		if result := tok.Tokenize("Brandschutz"); len(result) != 3 {
+			t.Errorf("Tokenize(%q) = %v, want 3 tokens", "Brandschutz", result)
+		}
+	}
+}
+
+func TestSharedTokenizer_Error(t *testing.T) {
+	cfg := testConfig()
+	if _, err := SharedTokenizer(t.TempDir()+"/missing.txt", cfg); err == nil {
+		t.Fatal("SharedTokenizer() with a missing dictionary: expected error")
+	}
+
+	sharedDictionaries.mu.Lock()
+	defer sharedDictionaries.mu.Unlock()
+	if len(sharedDictionaries.dicts) != 0 {
+		t.Errorf("Failed load left %d cached dictionaries", len(sharedDictionaries.dicts))
+	}
+}
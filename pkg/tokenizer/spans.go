@@ -0,0 +1,101 @@
+package tokenizer
+
+import "strings"
+
+// TokenSpan is an output token with the span of input it was derived from.
+type TokenSpan struct {
+	Text  string
+	Start int // Rune offset of the source word or segment in the input
+	End   int // Rune offset just past it
+}
+
+// TokenizeWithSpans is like Tokenize but reports where in text each token
+// came from. Spans are rune offsets into the original text, like RawToken's.
+//
+// Spans are taken from word and segment boundaries found before
+// normalization, never from the normalized token, so they point at the
+// original runes even when normalization changes a token's length:
+// "Straße" yields "strasse" spanning the six runes of "Straße", and
+// "Wärme" decomposed by NFKD still spans five runes. Tokens from a compound
+// segment span that segment; tokens derived from the whole word (the
+// lowercase original, n-grams, word variants) span the word. Format
+// characters removed before splitting keep their place in the offsets.
+//
+// Tokens are deduplicated within each word but not across words, so every
+// occurrence is reported with its own span.
+func (t *Tokenizer) TokenizeWithSpans(text string) []TokenSpan {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	offsets := t.originalOffsets(text)
+	// Joiners removed from words shift the runes after them. Format
+	// character removal drops them before splitting already.
+	var source []rune
+	if t.splitOptions.Joiners && offsets == nil && strings.ContainsAny(text, "\u200C\u200D") {
+		source = []rune(text)
+	}
+
+	var results []TokenSpan
+	seen := make(tokenSet)
+	for _, raw := range t.splitWords(text) {
+		if raw.Type == TokenSeparator {
+			continue
+		}
+		var kept []int
+		if source != nil {
+			kept = nonJoinerOffsets(source[raw.Start:raw.End])
+		}
+		clear(seen)
+		t.tokenizeRaw(raw, func(token string, origin tokenOrigin) {
+			if !seen.add(token) {
+				return
+			}
+			s := origin.span
+			// Expanded elision stubs carry text beyond their source runes
+			if n := raw.End - raw.Start; s.end > n {
+				s = span{0, n}
+			}
+			if kept != nil && s.end <= len(kept) {
+				s = span{kept[s.start], kept[s.end-1] + 1}
+			}
+			start, end := raw.Start+s.start, raw.Start+s.end
+			if offsets != nil {
+				start, end = offsets[start], offsets[end-1]+1
+			}
+			results = append(results, TokenSpan{Text: token, Start: start, End: end})
+		})
+	}
+	return results
+}
+
+// originalOffsets maps rune offsets of text after format character removal
+// back to offsets in text, or returns nil if splitWords leaves text as is.
+func (t *Tokenizer) originalOffsets(text string) []int {
+	if !t.stripFormatChars || strings.IndexFunc(text, isFormatChar) < 0 {
+		return nil
+	}
+	var offsets []int
+	i := 0
+	for _, r := range text {
+		if !isFormatChar(r) {
+			offsets = append(offsets, i)
+		}
+		i++
+	}
+	return offsets
+}
+
+// nonJoinerOffsets returns the offsets of the runes in word that are not
+// joiners, or nil if it contains none.
+func nonJoinerOffsets(word []rune) []int {
+	var offsets []int
+	for i, r := range word {
+		if !isJoiner(r) {
+			offsets = append(offsets, i)
+		}
+	}
+	if len(offsets) == len(word) {
+		return nil
+	}
+	return offsets
+}
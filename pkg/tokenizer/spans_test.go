@@ -0,0 +1,113 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer_TokenizeWithSpans(t *testing.T) {
+	dictPath := newTestDictionary(t, "straßen", "bahn", "wärme", "dämmung", "maße")
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	text := "Die Straßenbahn, Wärmedämmung und Maße"
+	runes := []rune(text)
+	source := func(s TokenSpan) string { return string(runes[s.Start:s.End]) }
+
+	result := tok.TokenizeWithSpans(text)
+	got := make(map[string]string)
+	for _, s := range result {
+		if s.Start < 0 || s.End > len(runes) || s.Start >= s.End {
+			t.Fatalf("Token %q has invalid span %d..%d", s.Text, s.Start, s.End)
+		}
+		got[s.Text] = source(s)
+	}
+
+	// Each token points at the original runes, whatever its normalized length
+	expected := map[string]string{
+		"die":          "Die",
+		"straßenbahn":  "Straßenbahn",
+		"strassen":     "Straßen", // ß→ss adds a rune
+		"bahn":         "bahn",
+		"wärmedämmung": "Wärmedämmung",
+		"warme":        "Wärme", // Combining mark removed after NFKD
+		"dammung":      "dämmung",
+		"und":          "und",
+		"maße":         "Maße",
+		"masse":        "Maße",
+	}
+	for token, want := range expected {
+		if got[token] != want {
+			t.Errorf("Token %q spans %q, want %q", token, got[token], want)
+		}
+	}
+}
+
+func TestTokenizer_TokenizeWithSpansRepeats(t *testing.T) {
+	dictPath := newTestDictionary(t, "haus")
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	// Every occurrence keeps its own span
+	expected := []TokenSpan{
+		{Text: "haus", Start: 0, End: 4},
+		{Text: "haus", Start: 9, End: 13},
+	}
+	if result := tok.TokenizeWithSpans("Haus und Haus"); !reflect.DeepEqual([]TokenSpan{result[0], result[len(result)-1]}, expected) {
+		t.Errorf("TokenizeWithSpans(%q) = %v, want first and last %v", "Haus und Haus", result, expected)
+	}
+}
+
+func TestTokenizer_TokenizeWithSpansFormatChars(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	cfg := testConfig()
+	cfg.Normalizers.RemoveFormatChars = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	// A soft hyphen inside the word and a zero-width space before it
+	text := "​Brand­schutz"
+	expected := []TokenSpan{
+		{Text: "brandschutz", Start: 1, End: 13},
+		{Text: "brand", Start: 1, End: 6},
+		{Text: "schutz", Start: 7, End: 13},
+	}
+	if result := tok.TokenizeWithSpans(text); !reflect.DeepEqual(result, expected) {
+		t.Errorf("TokenizeWithSpans(%q) = %v, want %v", text, result, expected)
+	}
+}
+
+func TestTokenizer_JoinersInWords(t *testing.T) {
+	dictPath := newTestDictionary(t, "auf", "lage")
+	cfg := testConfig()
+	cfg.JoinersInWords = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	text := "Neue Auf\u200clage"
+	if result, expected := tok.Tokenize(text), []string{"neue", "auflage", "auf", "lage"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) = %v, want %v", text, result, expected)
+	}
+
+	expected := []TokenSpan{
+		{Text: "neue", Start: 0, End: 4},
+		{Text: "auflage", Start: 5, End: 13},
+		{Text: "auf", Start: 5, End: 8},
+		{Text: "lage", Start: 9, End: 13},
+	}
+	if result := tok.TokenizeWithSpans(text); !reflect.DeepEqual(result, expected) {
+		t.Errorf("TokenizeWithSpans(%q) = %v, want %v", text, result, expected)
+	}
+}
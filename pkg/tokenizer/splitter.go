@@ -1,6 +1,8 @@
 package tokenizer
 
 import (
+	"fmt"
+	"strings"
 	"unicode"
 )
 
@@ -10,8 +12,49 @@ type TokenType int
 const (
 	TokenWord TokenType = iota
 	TokenSeparator
+	TokenSymbol // A single rune from SplitOptions.Symbols
 )
 
+// tokenTypeNames are the names TokenType is written as in JSON and logs.
+var tokenTypeNames = map[TokenType]string{
+	TokenWord:      "word",
+	TokenSeparator: "separator",
+	TokenSymbol:    "symbol",
+}
+
+// String returns "word", "separator" or "symbol".
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the type by name, so a RawToken marshals to JSON as
+// {"Text": "Haus", "Type": "word", ...}.
+func (t TokenType) MarshalText() ([]byte, error) {
+	name, ok := tokenTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("invalid token type %d", t)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a type name written by MarshalText.
+func (t *TokenType) UnmarshalText(text []byte) error {
+	for typ, name := range tokenTypeNames {
+		if name == string(text) {
+			*t = typ
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown token type %q", text)
+}
+
+// DefaultSymbols are currency, percent, section and math symbols that are
+// meaningful in German financial and legal text.
+const DefaultSymbols = "€$£¥¢%‰§¶°+−±×÷=<>"
+
 // RawToken represents a token before normalization.
 type RawToken struct {
 	Text  string
@@ -20,41 +63,229 @@ type RawToken struct {
 	End   int
 }
 
+// SplitOptions enables optional rules in SplitWordsWithOptions.
+type SplitOptions struct {
+	// Abbreviations are kept as single words ("z.B.") instead of being
+	// split at their periods. Nil disables abbreviation matching.
+	Abbreviations *Abbreviations
+
+	// GermanNumbers keeps German-formatted numbers as single words:
+	// thousands dots and decimal commas ("1.000,50", "3,14") and ordinals
+	// ("3." when followed by a space and another word, as in "am 3. Mai").
+	GermanNumbers bool
+
+	// Symbols lists runes emitted as single-rune TokenSymbol tokens, so
+	// "20 %" keeps its "%" apart from the whitespace around it. Runes that
+	// would otherwise be word characters are unaffected. Empty classifies
+	// all symbols as separators.
+	Symbols string
+
+	// IsWordRune replaces the default classification of word characters
+	// (letters and numbers), e.g. to keep "@" and "." inside email-like
+	// words. Nil uses the default.
+	IsWordRune func(r rune) bool
+
+	// Joiners keeps zero-width joiners (ZWNJ U+200C, ZWJ U+200D) between
+	// two word characters inside the word, so the ligature control in
+	// "Auf\u200clage" doesn't split it. The joiner stays in Text; see
+	// StripJoiners. Joiners elsewhere are separators.
+	Joiners bool
+}
+
 // SplitWords splits text into words and separators.
 // Word characters: letters and numbers.
 // Separators: whitespace, punctuation, symbols.
 func SplitWords(text string) []RawToken {
+	return SplitWordsWithOptions(text, SplitOptions{})
+}
+
+// SplitWordsWithAbbreviations is like SplitWords but emits known
+// abbreviations such as "z.B." as a single word token instead of splitting
+// at their periods. A nil set behaves like SplitWords.
+func SplitWordsWithAbbreviations(text string, abbrevs *Abbreviations) []RawToken {
+	return SplitWordsWithOptions(text, SplitOptions{Abbreviations: abbrevs})
+}
+
+// SplitWordsFunc is like SplitWords but classifies runes with isWordRune:
+// maximal runs of runes it accepts become TokenWord tokens, everything else
+// TokenSeparator tokens. Concatenating the Text of all returned tokens
+// reproduces the input.
+func SplitWordsFunc(text string, isWordRune func(rune) bool) []RawToken {
+	return SplitWordsWithOptions(text, SplitOptions{IsWordRune: isWordRune})
+}
+
+// SplitWordsWithOptions is like SplitWords with the optional rules in opts.
+// Concatenating the Text of all returned tokens reproduces the input.
+func SplitWordsWithOptions(text string, opts SplitOptions) []RawToken {
 	var tokens []RawToken
 	runes := []rune(text)
 
-	if len(runes) == 0 {
-		return tokens
+	for i := 0; i < len(runes); {
+		n := opts.Abbreviations.match(runes, i)
+		if n == 0 && opts.GermanNumbers {
+			n = matchGermanNumber(runes, i)
+		}
+		if n > 0 {
+			tokens = append(tokens, RawToken{
+				Text:  string(runes[i : i+n]),
+				Type:  TokenWord,
+				Start: i,
+				End:   i + n,
+			})
+			i += n
+			continue
+		}
+
+		currentType := opts.tokenType(runes[i])
+		end := i + 1
+		for currentType != TokenSymbol && end < len(runes) {
+			if opts.tokenType(runes[end]) == currentType {
+				end++
+			} else if n := opts.joinerRun(runes, end); n > 0 && currentType == TokenWord {
+				end += n
+			} else {
+				break
+			}
+		}
+		tokens = append(tokens, RawToken{
+			Text:  string(runes[i:end]),
+			Type:  currentType,
+			Start: i,
+			End:   end,
+		})
+		i = end
 	}
 
-	start := 0
-	currentType := getTokenType(runes[0])
-
-	for i := 1; i <= len(runes); i++ {
-		var nextType TokenType
-		if i < len(runes) {
-			nextType = getTokenType(runes[i])
-		} else {
-			nextType = TokenType(-1) // Force flush
+	return tokens
+}
+
+// matchGermanNumber returns the length in runes of a German-formatted number
+// starting at runes[i], or 0 if there is none or it needs no special
+// handling (plain digit runs are already single words):
+//
+//	1.000.000   thousands dots (groups of exactly three digits)
+//	1.000,50    thousands dots and decimal comma
+//	3,14        decimal comma
+//	3.          ordinal, only when followed by a space and a word ("am 3. Mai")
+//
+// The number must not run straight into a letter or digit.
+func matchGermanNumber(runes []rune, i int) int {
+	digits := func(from int) int {
+		n := 0
+		for from+n < len(runes) && isDigit(runes[from+n]) {
+			n++
 		}
+		return n
+	}
 
-		if nextType != currentType {
-			tokens = append(tokens, RawToken{
-				Text:  string(runes[start:i]),
-				Type:  currentType,
-				Start: start,
-				End:   i,
-			})
+	lead := digits(i)
+	if lead == 0 {
+		return 0
+	}
+	end := i + lead
+
+	// Thousands groups: a leading group of 1-3 digits, then ".ddd" groups
+	if lead <= 3 {
+		for end+4 <= len(runes) && runes[end] == '.' && digits(end+1) == 3 {
+			end += 4
+		}
+	}
+
+	// Decimal comma
+	if end+1 < len(runes) && runes[end] == ',' {
+		if n := digits(end + 1); n > 0 {
+			end += 1 + n
+		}
+	}
+
+	if end == i+lead {
+		// Ordinal: "3. Mai", but not "3." at the end of the text
+		if end+2 < len(runes) && runes[end] == '.' && unicode.IsSpace(runes[end+1]) &&
+			getTokenType(runes[end+2]) == TokenWord {
+			return lead + 1
+		}
+		return 0
+	}
+	if end < len(runes) && getTokenType(runes[end]) == TokenWord {
+		return 0
+	}
+	return end - i
+}
+
+// isDigit reports whether r is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// splitAtDigits splits word into alternating runs of digits and non-digits:
+// "typ2stecker" becomes [typ 2 stecker].
+func splitAtDigits(word string) []string {
+	var runs []string
+	start := 0
+	prevDigit := false
+	for i, r := range word {
+		digit := unicode.IsDigit(r)
+		if i > 0 && digit != prevDigit {
+			runs = append(runs, word[start:i])
 			start = i
-			currentType = nextType
 		}
+		prevDigit = digit
 	}
+	if start < len(word) {
+		runs = append(runs, word[start:])
+	}
+	return runs
+}
 
-	return tokens
+// tokenType classifies r like getTokenType, or by IsWordRune if set,
+// except that separators listed in Symbols are symbols.
+func (o SplitOptions) tokenType(r rune) TokenType {
+	typ := getTokenType(r)
+	if o.IsWordRune != nil {
+		typ = TokenSeparator
+		if o.IsWordRune(r) {
+			typ = TokenWord
+		}
+	}
+	if typ == TokenSeparator && o.Symbols != "" && strings.ContainsRune(o.Symbols, r) {
+		return TokenSymbol
+	}
+	return typ
+}
+
+// joinerRun returns the length of the run of joiners at runes[i] if Joiners
+// is enabled and a word character follows it, or 0.
+func (o SplitOptions) joinerRun(runes []rune, i int) int {
+	if !o.Joiners {
+		return 0
+	}
+	end := i
+	for end < len(runes) && isJoiner(runes[end]) {
+		end++
+	}
+	if end == i || end == len(runes) || o.tokenType(runes[end]) != TokenWord {
+		return 0
+	}
+	return end - i
+}
+
+// isJoiner reports whether r is a zero-width non-joiner or joiner.
+func isJoiner(r rune) bool {
+	return r == '\u200C' || r == '\u200D'
+}
+
+// StripJoiners removes zero-width non-joiners and joiners (U+200C, U+200D)
+// from s.
+func StripJoiners(s string) string {
+	if !strings.ContainsAny(s, "\u200C\u200D") {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isJoiner(r) {
+			return -1
+		}
+		return r
+	}, s)
 }
 
 // getTokenType determines if a rune is a word character or separator.
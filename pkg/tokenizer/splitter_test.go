@@ -1,7 +1,11 @@
 package tokenizer
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+	"unicode"
 )
 
 func TestSplitWords(t *testing.T) {
@@ -88,3 +92,222 @@ func TestGetTokenType(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitWordsWithOptions_GermanNumbers(t *testing.T) {
+	opts := SplitOptions{GermanNumbers: true}
+
+	tests := []struct {
+		input    string
+		expected []string // Word tokens only
+	}{
+		{"1.000,50 Euro", []string{"1.000,50", "Euro"}},
+		{"Pi ist 3,14.", []string{"Pi", "ist", "3,14"}},
+		{"1.000.000 Menschen", []string{"1.000.000", "Menschen"}},
+		{"am 3. Mai", []string{"am", "3.", "Mai"}},
+		{"Es waren 3.", []string{"Es", "waren", "3"}}, // Sentence-final period
+		{"3.14", []string{"3", "14"}},                 // Not a German thousands group
+		{"1, 2 und 3", []string{"1", "2", "und", "3"}},
+		{"3,5kg", []string{"3", "5kg"}}, // Runs into a letter
+		{"123abc", []string{"123abc"}},
+	}
+
+	for _, tt := range tests {
+		var words []string
+		for _, tok := range SplitWordsWithOptions(tt.input, opts) {
+			if tok.Type == TokenWord {
+				words = append(words, tok.Text)
+			}
+		}
+		if strings.Join(words, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("SplitWordsWithOptions(%q) words = %q, want %q", tt.input, words, tt.expected)
+		}
+	}
+
+	// Without the option, numbers split at punctuation as before
+	var words []string
+	for _, tok := range SplitWords("1.000,50") {
+		if tok.Type == TokenWord {
+			words = append(words, tok.Text)
+		}
+	}
+	if strings.Join(words, "|") != "1|000|50" {
+		t.Errorf("SplitWords(%q) words = %q, want [1 000 50]", "1.000,50", words)
+	}
+}
+
+func TestSplitWordsWithOptions_Symbols(t *testing.T) {
+	opts := SplitOptions{Symbols: DefaultSymbols}
+
+	tests := []struct {
+		input    string
+		expected []RawToken
+	}{
+		{"§ 5", []RawToken{
+			{"§", TokenSymbol, 0, 1},
+			{" ", TokenSeparator, 1, 2},
+			{"5", TokenWord, 2, 3},
+		}},
+		{"20 %", []RawToken{
+			{"20", TokenWord, 0, 2},
+			{" ", TokenSeparator, 2, 3},
+			{"%", TokenSymbol, 3, 4},
+		}},
+		// Each symbol is a token of its own, and unlisted punctuation stays a separator
+		{"5€€.", []RawToken{
+			{"5", TokenWord, 0, 1},
+			{"€", TokenSymbol, 1, 2},
+			{"€", TokenSymbol, 2, 3},
+			{".", TokenSeparator, 3, 4},
+		}},
+	}
+
+	for _, tt := range tests {
+		result := SplitWordsWithOptions(tt.input, opts)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("SplitWordsWithOptions(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+
+	// Without Symbols, symbols are separators as before
+	for _, tok := range SplitWords("§ 5 €") {
+		if tok.Type == TokenSymbol {
+			t.Errorf("SplitWords() emitted symbol %q without Symbols", tok.Text)
+		}
+	}
+
+	// Only listed runes are symbols
+	for _, r := range "€§%" {
+		if typ := opts.tokenType(r); typ != TokenSymbol {
+			t.Errorf("tokenType(%q) = %v, want TokenSymbol", r, typ)
+		}
+	}
+	euro := SplitOptions{Symbols: "€"}
+	for _, r := range " ,a" {
+		if typ := euro.tokenType(r); typ == TokenSymbol {
+			t.Errorf("tokenType(%q) = TokenSymbol, want %v", r, getTokenType(r))
+		}
+	}
+}
+
+func TestSplitWordsFunc(t *testing.T) {
+	isEmailRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '@' || r == '.'
+	}
+
+	input := "Mail an max.muster@firma.de, bitte."
+	var words []string
+	for _, tok := range SplitWordsFunc(input, isEmailRune) {
+		if tok.Type == TokenWord {
+			words = append(words, tok.Text)
+		}
+	}
+	expected := []string{"Mail", "an", "max.muster@firma.de", "bitte."}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("SplitWordsFunc(%q) words = %q, want %q", input, words, expected)
+	}
+
+	// The tokens still reproduce the input
+	var b strings.Builder
+	for _, tok := range SplitWordsFunc(input, isEmailRune) {
+		b.WriteString(tok.Text)
+	}
+	if b.String() != input {
+		t.Errorf("SplitWordsFunc(%q) tokens join to %q", input, b.String())
+	}
+
+	// The default splitter is unchanged
+	var defaultWords []string
+	for _, tok := range SplitWords("max.muster@firma.de") {
+		if tok.Type == TokenWord {
+			defaultWords = append(defaultWords, tok.Text)
+		}
+	}
+	if expected := []string{"max", "muster", "firma", "de"}; !reflect.DeepEqual(defaultWords, expected) {
+		t.Errorf("SplitWords() words = %q, want %q", defaultWords, expected)
+	}
+}
+
+func TestSplitWordsWithOptions_Joiners(t *testing.T) {
+	input := "Auf\u200clage und\u200d \u200cSchrift\u200c\u200dart"
+	var words []string
+	var b strings.Builder
+	for _, tok := range SplitWordsWithOptions(input, SplitOptions{Joiners: true}) {
+		if tok.Type == TokenWord {
+			words = append(words, tok.Text)
+		}
+		b.WriteString(tok.Text)
+	}
+	// Joiners at word edges still separate
+	expected := []string{"Auf\u200clage", "und", "Schrift\u200c\u200dart"}
+	if !reflect.DeepEqual(words, expected) {
+		t.Errorf("SplitWordsWithOptions(%q) words = %q, want %q", input, words, expected)
+	}
+	if b.String() != input {
+		t.Errorf("SplitWordsWithOptions(%q) tokens join to %q", input, b.String())
+	}
+
+	if got := len(SplitWords("Auf\u200clage")); got != 3 {
+		t.Errorf("SplitWords(%q) returned %d tokens, want 3", "Auf\u200clage", got)
+	}
+	if got := StripJoiners("Auf\u200clage"); got != "Auflage" {
+		t.Errorf("StripJoiners(%q) = %q, want %q", "Auf\u200clage", got, "Auflage")
+	}
+}
+
+func TestSplitAtDigits(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"typ2stecker", []string{"typ", "2", "stecker"}},
+		{"din4format", []string{"din", "4", "format"}},
+		{"a380", []string{"a", "380"}},
+		{"4you", []string{"4", "you"}},
+		{"größe3", []string{"größe", "3"}},
+		{"haus", []string{"haus"}},
+		{"2024", []string{"2024"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		result := splitAtDigits(tt.input)
+		if strings.Join(result, "|") != strings.Join(tt.expected, "|") || len(result) != len(tt.expected) {
+			t.Errorf("splitAtDigits(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestRawToken_JSON(t *testing.T) {
+	tokens := SplitWordsWithOptions("Haus 5 €", SplitOptions{Symbols: DefaultSymbols})
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	expected := `[{"Text":"Haus","Type":"word","Start":0,"End":4},` +
+		`{"Text":" ","Type":"separator","Start":4,"End":5},` +
+		`{"Text":"5","Type":"word","Start":5,"End":6},` +
+		`{"Text":" ","Type":"separator","Start":6,"End":7},` +
+		`{"Text":"€","Type":"symbol","Start":7,"End":8}]`
+	if string(data) != expected {
+		t.Errorf("json.Marshal() = %s, want %s", data, expected)
+	}
+
+	var decoded []RawToken
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, tokens) {
+		t.Errorf("round trip = %v, want %v", decoded, tokens)
+	}
+
+	var typ TokenType
+	if err := json.Unmarshal([]byte(`"number"`), &typ); err == nil {
+		t.Error("json.Unmarshal(\"number\") succeeded, want error")
+	}
+	if _, err := json.Marshal(TokenType(42)); err == nil {
+		t.Error("json.Marshal(TokenType(42)) succeeded, want error")
+	}
+	if TokenSeparator.String() != "separator" {
+		t.Errorf("TokenSeparator.String() = %q, want %q", TokenSeparator.String(), "separator")
+	}
+}
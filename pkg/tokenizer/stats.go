@@ -0,0 +1,86 @@
+package tokenizer
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// DefaultStatsTopN is how many prefixes and suffixes Stats reports.
+const DefaultStatsTopN = 10
+
+// statsAffixLength is the length, in runes, of the prefixes and suffixes
+// Stats counts. Shorter words are not counted.
+const statsAffixLength = 3
+
+// AffixCount is a prefix or suffix and the number of words that have it.
+type AffixCount struct {
+	Affix string
+	Count int
+}
+
+// DictStats describes the shape of a dictionary's word set, for spotting
+// junk entries such as a spike of two-letter words.
+type DictStats struct {
+	Words           int          // Number of unique words
+	LengthHistogram []int        // LengthHistogram[n] is the number of words of n runes
+	MeanLength      float64      // Mean word length in runes
+	TopPrefixes     []AffixCount // Most common 3-rune prefixes, most frequent first
+	TopSuffixes     []AffixCount // Most common 3-rune suffixes, most frequent first
+}
+
+// MaxLength returns the length in runes of the longest word, 0 if there
+// are none.
+func (s DictStats) MaxLength() int {
+	return max(len(s.LengthHistogram)-1, 0)
+}
+
+// Stats reports the word count, length distribution and the
+// DefaultStatsTopN most common prefixes and suffixes of the word set,
+// including unflushed changes. Ties are listed alphabetically.
+func (d *Dictionary) Stats() DictStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	stats := DictStats{Words: len(d.words)}
+	prefixes := make(map[string]int)
+	suffixes := make(map[string]int)
+	total := 0
+	for word := range d.words {
+		n := utf8.RuneCountInString(word)
+		for len(stats.LengthHistogram) <= n {
+			stats.LengthHistogram = append(stats.LengthHistogram, 0)
+		}
+		stats.LengthHistogram[n]++
+		total += n
+
+		if n >= statsAffixLength {
+			runes := []rune(word)
+			prefixes[string(runes[:statsAffixLength])]++
+			suffixes[string(runes[n-statsAffixLength:])]++
+		}
+	}
+	if stats.Words > 0 {
+		stats.MeanLength = float64(total) / float64(stats.Words)
+	}
+	stats.TopPrefixes = topAffixes(prefixes, DefaultStatsTopN)
+	stats.TopSuffixes = topAffixes(suffixes, DefaultStatsTopN)
+	return stats
+}
+
+// topAffixes returns the n most frequent affixes in counts.
+func topAffixes(counts map[string]int, n int) []AffixCount {
+	affixes := make([]AffixCount, 0, len(counts))
+	for affix, count := range counts {
+		affixes = append(affixes, AffixCount{Affix: affix, Count: count})
+	}
+	sort.Slice(affixes, func(i, j int) bool {
+		if affixes[i].Count != affixes[j].Count {
+			return affixes[i].Count > affixes[j].Count
+		}
+		return affixes[i].Affix < affixes[j].Affix
+	})
+	if len(affixes) > n {
+		affixes = affixes[:n]
+	}
+	return affixes
+}
@@ -0,0 +1,43 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDictionary_Stats(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "ab", "ei", "öl", "haus", "hand", "haut", "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load dictionary: %v", err)
+	}
+	defer dict.Close()
+
+	stats := dict.Stats()
+	if stats.Words != 8 {
+		t.Errorf("Stats().Words = %d, want 8", stats.Words)
+	}
+	// Lengths are counted in runes: "öl" has two
+	if expected := []int{0, 0, 3, 0, 3, 1, 1}; !reflect.DeepEqual(stats.LengthHistogram, expected) {
+		t.Errorf("Stats().LengthHistogram = %v, want %v", stats.LengthHistogram, expected)
+	}
+	if stats.MaxLength() != 6 {
+		t.Errorf("Stats().MaxLength() = %d, want 6", stats.MaxLength())
+	}
+	if stats.MeanLength != 3.625 {
+		t.Errorf("Stats().MeanLength = %v, want 3.625", stats.MeanLength)
+	}
+	if expected := []AffixCount{{"hau", 2}, {"bra", 1}, {"han", 1}, {"sch", 1}}; !reflect.DeepEqual(stats.TopPrefixes, expected) {
+		t.Errorf("Stats().TopPrefixes = %v, want %v", stats.TopPrefixes, expected)
+	}
+	if expected := []AffixCount{{"and", 2}, {"aus", 1}, {"aut", 1}, {"utz", 1}}; !reflect.DeepEqual(stats.TopSuffixes, expected) {
+		t.Errorf("Stats().TopSuffixes = %v, want %v", stats.TopSuffixes, expected)
+	}
+
+	// Unflushed changes are included
+	if err := dict.AddWord("xy"); err != nil {
+		t.Fatal(err)
+	}
+	if stats := dict.Stats(); stats.LengthHistogram[2] != 4 {
+		t.Errorf("Stats().LengthHistogram[2] after AddWord = %d, want 4", stats.LengthHistogram[2])
+	}
+}
@@ -0,0 +1,29 @@
+package tokenizer
+
+// germanStopWords are frequent German function words: articles, pronouns,
+// prepositions, conjunctions and auxiliary verbs.
+var germanStopWords = []string{
+	"aber", "als", "am", "an", "auch", "auf", "aus", "bei", "bin", "bis",
+	"bist", "da", "damit", "dann", "das", "dass", "dem", "den", "denn", "der",
+	"des", "die", "dies", "diese", "diesem", "diesen", "dieser", "dieses", "doch", "du",
+	"durch", "ein", "eine", "einem", "einen", "einer", "eines", "er", "es", "für",
+	"gegen", "hat", "hatte", "haben", "hier", "ich", "ihr", "ihre", "im", "in",
+	"ist", "ja", "jede", "jedem", "jeden", "jeder", "jedes", "kann", "kein", "keine",
+	"man", "mit", "nach", "nicht", "noch", "nur", "ob", "oder", "ohne", "sein",
+	"seine", "sich", "sie", "sind", "so", "über", "um", "und", "uns", "unter",
+	"vom", "von", "vor", "war", "waren", "was", "weil", "wenn", "werden", "wie",
+	"wir", "wird", "wo", "zu", "zum", "zur", "zwischen",
+}
+
+// germanStopWordSet is the shared, read-only set of germanStopWords.
+var germanStopWordSet = DefaultGermanStopWords()
+
+// DefaultGermanStopWords returns a new set of common German stop words for
+// Config.StopWords. The set is a fresh copy and may be modified.
+func DefaultGermanStopWords() map[string]struct{} {
+	set := make(map[string]struct{}, len(germanStopWords))
+	for _, w := range germanStopWords {
+		set[w] = struct{}{}
+	}
+	return set
+}
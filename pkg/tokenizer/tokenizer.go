@@ -1,63 +1,391 @@
 package tokenizer
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned by TokenizeValidated for input that isn't valid UTF-8.
+var ErrInvalidUTF8 = errors.New("input is not valid UTF-8")
+
 // Config holds all tokenizer configuration. All fields must be explicitly set.
 type Config struct {
-	Cache             bool
-	LowercaseOriginal bool
-	Normalizers       NormalizerConfig
+	Cache              bool             `json:"cache"`
+	CacheSize          int              `json:"cache_size"` // Only with Cache; 0 disables caching
+	LowercaseOriginal  bool             `json:"lowercase_original"`
+	AlwaysEmitWhole    bool             `json:"always_emit_whole"`  // Emit the normalized whole compound alongside its segments
+	DedupAcrossForms   bool             `json:"dedup_across_forms"` // Drop the lowercase original when the word's normalized form is emitted
+	EmitVariants       bool             `json:"emit_variants"`
+	EmitCaseTag        bool             `json:"emit_case_tag"`
+	EmitEszettVariants bool             `json:"emit_eszett_variants"` // Emit both "maße" and "masse" for words with ß
+	MaxWordLength      int              `json:"max_word_length"`      // 0 uses DefaultMaxWordLength
+	MaxSegments        int              `json:"max_segments"`         // 0 means unlimited
+	MaxLookups         int              `json:"max_lookups"`          // Candidate checks per word before giving up, 0 means unlimited
+	RecursiveSplit     bool             `json:"recursive_split"`      // Also decompose segments that are compounds themselves
+	DigitBoundaries    bool             `json:"digit_boundaries"`     // Split words at letter/digit transitions ("typ2stecker" → typ, 2, stecker)
+	TrieBackend        bool             `json:"trie_backend"`         // Find split candidates with an in-memory trie
+	BloomFilter        bool             `json:"bloom_filter"`         // Reject most non-words before the FST lookup
+	Normalizers        NormalizerConfig `json:"normalizers"`
+
+	// NormalizeBeforeSplit folds umlauts and ß (ä→a, ß→ss) in the whole word
+	// before splitting, for dictionaries stored in that folded form, so the
+	// splitter looks up each candidate once instead of also trying its
+	// folded spelling. Segments are emitted folded ("Wärmedämmung" →
+	// "warme", "dammung"); spans still point at the source runes.
+	NormalizeBeforeSplit bool `json:"normalize_before_split"`
+
+	// PrefixFallback retries words that don't split and aren't dictionary
+	// entries with a prefix from GermanPrefixes stripped ("vorgehen" →
+	// "vor", "gehen").
+	PrefixFallback bool `json:"prefix_fallback"`
+
+	// SplitThreshold keeps compounds whose split scores below it (see
+	// CompoundSplitter.Confidence) whole: only the normalized word is
+	// emitted, not its segments, trading recall for precision on
+	// accidental splits such as "Eisen" → "ei", "sen". 0 disables it.
+	SplitThreshold float64 `json:"split_threshold"`
+
+	// AbbreviationsPath names an abbreviation list ("z.B.", "usw.") whose
+	// entries are kept as single words. Empty disables abbreviation matching.
+	AbbreviationsPath string `json:"abbreviations_path,omitempty"`
+
+	// GermanNumbers keeps "1.000,50", "3,14" and ordinals like "3." as
+	// single tokens instead of splitting them at the punctuation.
+	GermanNumbers bool `json:"german_numbers"`
+
+	// Symbols lists runes such as "€", "%" and "§" that are emitted as
+	// tokens of their own instead of being dropped with the separators, so
+	// "§ 5" yields "§" and "5". Empty drops all symbols; DefaultSymbols
+	// covers currency and math symbols.
+	Symbols string `json:"symbols,omitempty"`
+
+	// JoinersInWords keeps zero-width joiners (ZWNJ, ZWJ) inside words
+	// from splitting them and removes them from the word, so the ligature
+	// control in "Auf\u200clage" yields "auflage". Spans still point at the
+	// source runes.
+	JoinersInWords bool `json:"joiners_in_words"`
+
+	// ExpandElisions reconstructs hyphenated stubs of coordinated compounds:
+	// "Ein- und Ausgang" is tokenized as "Eingang und Ausgang".
+	ExpandElisions bool `json:"expand_elisions"`
+
+	// SubwordFallback emits character n-grams for words that neither split
+	// nor match the dictionary. Zero NgramMin/NgramMax use the defaults.
+	SubwordFallback bool `json:"subword_fallback"`
+	NgramMin        int  `json:"ngram_min"`
+	NgramMax        int  `json:"ngram_max"`
+
+	// PassThroughNonLatin emits words written mostly in a non-Latin script
+	// (Cyrillic, Greek, CJK) lowercased only, skipping compound splitting,
+	// stemming and umlaut handling, which are meaningless for them.
+	PassThroughNonLatin bool `json:"pass_through_non_latin"`
+
+	// OutputCase recases emitted tokens for display: OutputOriginal takes
+	// the casing of the source text ("Brand", "schutz"), OutputTitle
+	// uppercases each token's first letter. Casing is applied before
+	// TokenFilter and deduplication. The default keeps tokens lowercase.
+	OutputCase OutputCase `json:"output_case,omitempty"`
+
+	// SegmentNormalization emits the segments of compounds lowercased only
+	// or as written in the input instead of fully normalized, e.g. for
+	// display. Splitting is unaffected, and unsplit words, whole compounds
+	// and n-grams stay fully normalized.
+	SegmentNormalization SegmentNormalization `json:"segment_normalization,omitempty"`
+
+	// StemNounsOnly restricts stemming and lemmatization to words that look
+	// like nouns: capitalized in the input and not a German function word,
+	// which may be capitalized at the start of a sentence. Other words, such
+	// as lowercase verbs ("haben"), are normalized without stemming.
+	StemNounsOnly bool `json:"stem_nouns_only"`
+
+	// StopWords lists lowercase words to drop from the output: words in the
+	// set produce no tokens, and matching compound segments are skipped.
+	// Nil keeps all words. Not loaded from JSON; set it after LoadConfig.
+	StopWords map[string]struct{} `json:"-"`
+
+	// TokenFilter post-processes every output token before deduplication:
+	// returning false drops the token, otherwise the returned string
+	// replaces it. Nil keeps tokens as they are. Not loaded from JSON.
+	TokenFilter TokenFilter `json:"-"`
+
+	// Logger receives dictionary events (FST rebuilds, rejected FST files,
+	// reloads) and, at debug level, words that could not be split. Nil
+	// keeps the tokenizer silent. Not loaded from JSON.
+	Logger *slog.Logger `json:"-"`
+
+	// Metrics receives token counts, split timings and fallbacks, e.g. for
+	// Prometheus. Nil disables the hooks. Not loaded from JSON.
+	Metrics Metrics `json:"-"`
+
+	// BatchWorkers bounds the goroutines used by TokenizeBatch (0 = GOMAXPROCS).
+	BatchWorkers int `json:"batch_workers"`
 }
 
+// TokenFilter transforms an output token, or drops it by returning false.
+type TokenFilter func(token string) (string, bool)
+
+// Default character n-gram sizes for SubwordFallback.
+const (
+	DefaultNgramMin = 3
+	DefaultNgramMax = 5
+)
+
+// Boundary markers wrapped around a word before n-gram extraction, so
+// prefixes and suffixes produce distinct n-grams ("<ha" vs "ha").
+const (
+	ngramStart = '<'
+	ngramEnd   = '>'
+)
+
 // NormalizerConfig specifies which normalization steps to apply.
 // Each step must be explicitly enabled or disabled.
 type NormalizerConfig struct {
-	NFKDDecompose        bool
-	RemoveControlChars   bool
-	Lowercase            bool
-	NormalizeQuotes      bool
-	ExpandLigatures      bool
-	ConvertEszett        bool
-	RemoveCombiningMarks bool
-	StemGerman           bool
+	NFKDDecompose        bool              `json:"nfkd_decompose"`
+	NormalizationForm    NormalizationForm `json:"normalization_form"` // Form step in NFKDDecompose's place, exclusive with it
+	RemoveControlChars   bool              `json:"remove_control_chars"`
+	RemoveFormatChars    bool              `json:"remove_format_chars"` // Also applied before word splitting
+	CollapseRepeats      bool              `json:"collapse_repeats"`    // Runs before NFKDDecompose
+	ExpandArchaic        bool              `json:"expand_archaic"`      // Historical letters: ſ→s, ſz→ß, aͤ→ä; runs first
+	Lowercase            bool              `json:"lowercase"`
+	LowercaseGerman      bool              `json:"lowercase_german"` // Lowercase with German case rules instead, see LowercaseGerman
+	NormalizeQuotes      bool              `json:"normalize_quotes"`
+	ExpandLigatures      bool              `json:"expand_ligatures"`
+	ConvertEszett        bool              `json:"convert_eszett"`
+	UmlautToDigraph      bool              `json:"umlaut_to_digraph"`
+	RemoveCombiningMarks bool              `json:"remove_combining_marks"` // All marks: umlaut dots and accents
+	RemoveUmlautMarks    bool              `json:"remove_umlaut_marks"`    // Only umlaut dots (Wärme→warme, Café kept); implied by RemoveCombiningMarks
+	RemoveAccentMarks    bool              `json:"remove_accent_marks"`    // Only other marks (Café→cafe, Wärme kept); implied by RemoveCombiningMarks
+	StemGerman           bool              `json:"stem_german"`
+
+	// NormalizeHistoricalSpelling retries words that miss the dictionary
+	// with artifacts of the old hyphenation rules mapped back to modern
+	// spellings (Zukker→Zucker, Kazze→Katze), see NormalizeHistoricalSpelling.
+	// Like the confusables, the modern form is used only if it resolves.
+	NormalizeHistoricalSpelling bool `json:"normalize_historical_spelling"`
+
+	// Stemmer replaces StemGerman as the stemming step when set.
+	// Words shorter than MinStemLength runes are not stemmed (0 stems everything).
+	Stemmer       NormalizerFunc `json:"-"`
+	MinStemLength int            `json:"min_stem_length"`
+
+	// Lemmatize maps words through the Lemmas table in the stemming position.
+	// Words missing from the table are stemmed if StemGerman is also set.
+	Lemmatize bool              `json:"lemmatize"`
+	Lemmas    map[string]string `json:"lemmas,omitempty"`
+
+	// NormalizeConfusables corrects OCR confusions ("rn" read for "m", "0"
+	// for "o") using the Confusables table, or DefaultConfusables if nil.
+	// Corrections are only tried, before word splitting, for words that miss
+	// the dictionary, and kept only if the corrected word resolves.
+	NormalizeConfusables bool              `json:"normalize_confusables"`
+	Confusables          map[string]string `json:"confusables,omitempty"`
+
+	// Custom holds user-supplied steps keyed by name. Only the names listed
+	// in CustomOrder are applied. Custom cannot be loaded from JSON; register
+	// the functions after LoadConfig.
+	Custom         map[string]NormalizerFunc `json:"-"`
+	CustomOrder    []string                  `json:"custom_order,omitempty"`
+	CustomPosition StepPosition              `json:"custom_position"`
+}
+
+// SwissNormalizerConfig returns a normalizer config for Swiss Standard German.
+// Swiss orthography has no ß but keeps umlauts, so ß is converted to ss while
+// ä/ö/ü are preserved: "Straße" → "strasse", "Größe" → "grösse".
+// Stemming is disabled because the Snowball stemmer strips umlauts.
+func SwissNormalizerConfig() NormalizerConfig {
+	return NormalizerConfig{
+		NFKDDecompose:        false,
+		RemoveControlChars:   true,
+		RemoveFormatChars:    true,
+		Lowercase:            true,
+		NormalizeQuotes:      true,
+		ExpandLigatures:      true,
+		ConvertEszett:        true,
+		RemoveCombiningMarks: false,
+		StemGerman:           false,
+	}
+}
+
+// StepPosition selects where custom normalizer steps run relative to the built-ins.
+type StepPosition int
+
+const (
+	// CustomBeforeStem runs custom steps after character normalization, right before stemming.
+	CustomBeforeStem StepPosition = iota
+	// CustomFirst runs custom steps before all built-in steps.
+	CustomFirst
+	// CustomLast runs custom steps after all built-in steps.
+	CustomLast
+)
+
+// SegmentNormalization selects how the segments of a word are emitted.
+type SegmentNormalization int
+
+const (
+	// SegmentFull runs segments through the full normalizer: "warme", "dammung".
+	SegmentFull SegmentNormalization = iota
+	// SegmentLowercaseOnly lowercases segments in their source spelling,
+	// keeping umlauts and ß: "wärme", "dämmung".
+	SegmentLowercaseOnly
+	// SegmentNone emits segments in their source spelling: "Wärme", "dämmung".
+	SegmentNone
+)
+
+// customSteps resolves CustomOrder against Custom.
+func (nc NormalizerConfig) customSteps() ([]namedStep, error) {
+	steps := make([]namedStep, 0, len(nc.CustomOrder))
+	for _, name := range nc.CustomOrder {
+		step, ok := nc.Custom[name]
+		if !ok || step == nil {
+			return nil, fmt.Errorf("unknown custom normalizer step %q", name)
+		}
+		steps = append(steps, namedStep{name, step})
+	}
+	return steps, nil
+}
+
+// stemmer returns the configured stemming step, honoring MinStemLength.
+func (nc NormalizerConfig) stemmer() NormalizerFunc {
+	stem := nc.Stemmer
+	if stem == nil {
+		stem = StemGerman
+	}
+	if nc.MinStemLength <= 0 {
+		return stem
+	}
+
+	minLen := nc.MinStemLength
+	return func(s string) string {
+		if utf8.RuneCountInString(s) < minLen {
+			return s
+		}
+		return stem(s)
+	}
 }
 
 // buildNormalizer creates a Normalizer from the config.
-func (nc NormalizerConfig) buildNormalizer() *Normalizer {
-	var steps []NormalizerFunc
+func (nc NormalizerConfig) buildNormalizer() (*Normalizer, error) {
+	custom, err := nc.customSteps()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []namedStep
 
+	if nc.CustomPosition == CustomFirst {
+		steps = append(steps, custom...)
+	}
+	// Expand archaic letters before NFKD folds ſ to s and separates the
+	// superscript e from its vowel.
+	if nc.ExpandArchaic {
+		steps = append(steps, namedStep{"ExpandArchaic", ExpandArchaic})
+	}
+	// Collapse before NFKD splits umlauts into base letter + combining mark,
+	// which would hide "üüü" from the run detection.
+	if nc.CollapseRepeats {
+		steps = append(steps, namedStep{"CollapseRepeats", CollapseRepeats})
+	}
 	if nc.NFKDDecompose {
-		steps = append(steps, NFKDDecompose)
+		steps = append(steps, namedStep{"NFKDDecompose", NFKDDecompose})
+	}
+	if step, ok := nc.NormalizationForm.step(); ok {
+		steps = append(steps, step)
 	}
 	if nc.RemoveControlChars {
-		steps = append(steps, RemoveControlChars)
+		steps = append(steps, namedStep{"RemoveControlChars", RemoveControlChars})
 	}
-	if nc.Lowercase {
-		steps = append(steps, Lowercase)
+	if nc.RemoveFormatChars {
+		steps = append(steps, namedStep{"RemoveFormatChars", RemoveFormatChars})
+	}
+	if nc.LowercaseGerman {
+		steps = append(steps, namedStep{"LowercaseGerman", LowercaseGerman})
+	} else if nc.Lowercase {
+		steps = append(steps, namedStep{"Lowercase", Lowercase})
 	}
 	if nc.NormalizeQuotes {
-		steps = append(steps, NormalizeQuotes)
+		steps = append(steps, namedStep{"NormalizeQuotes", NormalizeQuotes})
 	}
 	if nc.ExpandLigatures {
-		steps = append(steps, ExpandLigatures)
+		steps = append(steps, namedStep{"ExpandLigatures", ExpandLigatures})
 	}
 	if nc.ConvertEszett {
-		steps = append(steps, ConvertEszett)
+		steps = append(steps, namedStep{"ConvertEszett", ConvertEszett})
+	}
+	if nc.UmlautToDigraph {
+		steps = append(steps, namedStep{"UmlautToDigraph", UmlautToDigraph})
 	}
-	if nc.RemoveCombiningMarks {
-		steps = append(steps, RemoveCombiningMarks)
+	if nc.RemoveCombiningMarks || (nc.RemoveUmlautMarks && nc.RemoveAccentMarks) {
+		steps = append(steps, namedStep{"RemoveCombiningMarks", RemoveCombiningMarks})
+	} else if nc.RemoveUmlautMarks {
+		steps = append(steps, namedStep{"RemoveUmlautMarks", RemoveUmlautMarks})
+	} else if nc.RemoveAccentMarks {
+		steps = append(steps, namedStep{"RemoveAccentMarks", RemoveAccentMarks})
 	}
-	if nc.StemGerman {
-		steps = append(steps, StemGerman)
+	if nc.CustomPosition == CustomBeforeStem {
+		steps = append(steps, custom...)
+	}
+	if nc.Lemmatize {
+		fallback := func(s string) string { return s }
+		if nc.StemGerman {
+			fallback = nc.stemmer()
+		}
+		steps = append(steps, namedStep{"LemmatizeGerman", lemmatizeWith(nc.Lemmas, fallback)})
+	} else if nc.StemGerman {
+		steps = append(steps, namedStep{"StemGerman", nc.stemmer()})
+	}
+	if nc.CustomPosition == CustomLast {
+		steps = append(steps, custom...)
 	}
 
-	return NewNormalizerWithSteps(steps...)
+	return newNamedNormalizer(steps), nil
 }
 
 // Tokenizer is the main German tokenizer.
+// Tokenize may be called concurrently, including while words are being added
+// or removed; cached splits from before a dictionary change are recomputed.
 type Tokenizer struct {
+	mu                       sync.RWMutex // Guards dict and splitter against ReloadDictionary
 	dict                     *Dictionary
+	release                  func() error // Returns a shared dict to the cache, nil if dict is private
 	normalizer               *Normalizer
+	eszettNormalizer         *Normalizer // Normalizer with ConvertEszett flipped, nil if unused
+	unstemmedNormalizer      *Normalizer // Normalizer without stemming, for TokenMeta.WasStemmed and StemNounsOnly
 	splitter                 *CompoundSplitter
 	includeLowercaseOriginal bool
+	alwaysEmitWhole          bool
+	dedupAcrossForms         bool
+	emitVariants             bool
+	emitCaseTag              bool
+	stripFormatChars         bool
+	historicalSpelling       bool
+	confusables              *confusableSet // OCR corrections to try, nil if disabled
+	stopWords                map[string]struct{}
+	tokenFilter              TokenFilter
+	passThroughNonLatin      bool
+	stemNounsOnly            bool
+	normalizeBeforeSplit     bool
+	outputCase               OutputCase
+	segmentNormalization     SegmentNormalization
+	logger                   *slog.Logger
+	metrics                  Metrics // Nil disables measurements
+	splitOptions             SplitOptions
+	expandElisions           bool
+	bloomFilter              bool
+	recursiveSplit           bool
+	digitBoundaries          bool
+	splitThreshold           float64
+	subwordFallback          bool
+	ngramMin                 int
+	ngramMax                 int
+	batchWorkers             int
 }
 
 // NewTokenizer creates a tokenizer with explicit configuration.
@@ -66,6 +394,7 @@ type Tokenizer struct {
 //
 //	tok, _ := NewTokenizer(dictPath, Config{
 //	    Cache:             true,
+//	    CacheSize:         CacheSize,
 //	    LowercaseOriginal: true,
 //	    Normalizers: NormalizerConfig{
 //	        NFKDDecompose:        true,
@@ -79,101 +408,1047 @@ type Tokenizer struct {
 //	    },
 //	})
 func NewTokenizer(dictPath string, cfg Config) (*Tokenizer, error) {
-	dict, err := NewDictionary(dictPath)
-	if err != nil {
+	return newTokenizer(cfg, func(logger *slog.Logger) (*Dictionary, func() error, error) {
+		dict, err := openDictionary(dictPath, cfg.BloomFilter, logger)
+		return dict, nil, err
+	})
+}
+
+// newTokenizer creates a tokenizer over the dictionary returned by open,
+// along with the function releasing it if it is shared.
+func newTokenizer(cfg Config, open func(logger *slog.Logger) (*Dictionary, func() error, error)) (*Tokenizer, error) {
+	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
 	// Build normalizer from config
-	normalizer := cfg.Normalizers.buildNormalizer()
+	normalizer, err := cfg.Normalizers.buildNormalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	unstemmed := cfg.Normalizers
+	unstemmed.StemGerman, unstemmed.Lemmatize = false, false
+	unstemmedNormalizer, err := unstemmed.buildNormalizer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eszettNormalizer *Normalizer
+	if cfg.EmitEszettVariants {
+		flipped := cfg.Normalizers
+		flipped.ConvertEszett = !flipped.ConvertEszett
+		if eszettNormalizer, err = flipped.buildNormalizer(); err != nil {
+			return nil, err
+		}
+	}
+
+	var abbreviations *Abbreviations
+	if cfg.AbbreviationsPath != "" {
+		abbreviations, err = LoadAbbreviations(cfg.AbbreviationsPath)
+		if err != nil {
+			return nil, fmt.Errorf("load abbreviations: %w", err)
+		}
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = nopLogger
+	}
+
+	dict, release, err := open(logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build compound splitter
-	var splitter *CompoundSplitter
+	splitterCfg := SplitterConfig{
+		MaxWordLength:  cfg.MaxWordLength,
+		MaxSegments:    cfg.MaxSegments,
+		MaxLookups:     cfg.MaxLookups,
+		PrefixFallback: cfg.PrefixFallback,
+		TrieBackend:    cfg.TrieBackend,
+		Metrics:        cfg.Metrics,
+	}
 	if cfg.Cache {
-		splitter = NewCompoundSplitter(dict)
-	} else {
-		splitter = NewCompoundSplitterNoCache(dict)
+		splitterCfg.CacheSize = cfg.CacheSize
+	}
+	splitter := NewCompoundSplitterWithConfig(dict, splitterCfg)
+
+	var confusables *confusableSet
+	if cfg.Normalizers.NormalizeConfusables {
+		table := cfg.Normalizers.Confusables
+		if table == nil {
+			table = DefaultConfusables
+		}
+		confusables = newConfusableSet(table)
 	}
 
 	return &Tokenizer{
 		dict:                     dict,
+		release:                  release,
 		normalizer:               normalizer,
+		eszettNormalizer:         eszettNormalizer,
+		unstemmedNormalizer:      unstemmedNormalizer,
 		splitter:                 splitter,
 		includeLowercaseOriginal: cfg.LowercaseOriginal,
+		alwaysEmitWhole:          cfg.AlwaysEmitWhole,
+		dedupAcrossForms:         cfg.DedupAcrossForms,
+		emitVariants:             cfg.EmitVariants,
+		emitCaseTag:              cfg.EmitCaseTag,
+		stripFormatChars:         cfg.Normalizers.RemoveFormatChars,
+		historicalSpelling:       cfg.Normalizers.NormalizeHistoricalSpelling,
+		confusables:              confusables,
+		stopWords:                cfg.StopWords,
+		tokenFilter:              cfg.TokenFilter,
+		passThroughNonLatin:      cfg.PassThroughNonLatin,
+		stemNounsOnly:            cfg.StemNounsOnly,
+		normalizeBeforeSplit:     cfg.NormalizeBeforeSplit,
+		outputCase:               cfg.OutputCase,
+		segmentNormalization:     cfg.SegmentNormalization,
+		logger:                   logger,
+		metrics:                  cfg.Metrics,
+		splitOptions:             SplitOptions{Abbreviations: abbreviations, GermanNumbers: cfg.GermanNumbers, Symbols: cfg.Symbols, Joiners: cfg.JoinersInWords},
+		expandElisions:           cfg.ExpandElisions,
+		bloomFilter:              cfg.BloomFilter,
+		recursiveSplit:           cfg.RecursiveSplit,
+		digitBoundaries:          cfg.DigitBoundaries,
+		splitThreshold:           cfg.SplitThreshold,
+		subwordFallback:          cfg.SubwordFallback,
+		ngramMin:                 orDefault(cfg.NgramMin, DefaultNgramMin),
+		ngramMax:                 orDefault(cfg.NgramMax, DefaultNgramMax),
+		batchWorkers:             cfg.BatchWorkers,
 	}, nil
 }
 
+// openDictionary loads a dictionary, enabling its bloom filter if requested.
+func openDictionary(path string, bloom bool, logger *slog.Logger) (*Dictionary, error) {
+	dict, err := NewDictionary(path, WithDictionaryLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+	if bloom {
+		if err := dict.EnableBloomFilter(DefaultBloomFalsePositiveRate); err != nil {
+			dict.Close()
+			return nil, err
+		}
+	}
+	return dict, nil
+}
+
 // Tokenize processes input text and returns deduplicated tokens.
 func (t *Tokenizer) Tokenize(text string) []string {
-	rawTokens := SplitWords(text)
+	return t.TokenizeInto(text, nil)
+}
 
-	resultSet := make(map[string]struct{})
-	var results []string
+// TokenizeInto is like Tokenize but appends the tokens to dst and returns
+// the extended slice. Passing the previous result as dst[:0] reuses its
+// storage, and the deduplication set is pooled, so repeated calls allocate
+// far less. Tokens already in dst don't take part in deduplication.
+func (t *Tokenizer) TokenizeInto(text string, dst []string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	rawTokens := t.splitWords(text)
+
+	seen := getTokenSet()
+	defer putTokenSet(seen)
+	start := len(dst)
+	emit := func(token string, _ tokenOrigin) {
+		if seen.add(token) {
+			dst = append(dst, token)
+		}
+	}
 
 	for _, raw := range rawTokens {
-		if raw.Type != TokenWord {
+		t.tokenizeRaw(raw, emit)
+	}
+
+	if t.metrics != nil {
+		t.metrics.TokensProduced(len(dst) - start)
+	}
+	return dst
+}
+
+// TokenizeWords is like Tokenize for text that is already split into
+// words: each element is tokenized as a single word, punctuation and
+// whitespace included, instead of being re-split by SplitWords. Tokens are
+// deduplicated across all words, and empty elements produce none.
+func (t *Tokenizer) TokenizeWords(words []string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var tokens []string
+	seen := getTokenSet()
+	defer putTokenSet(seen)
+	emit := func(token string, _ tokenOrigin) {
+		if seen.add(token) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, word := range words {
+		if word != "" {
+			t.tokenizeWord(word, emit)
+		}
+	}
+
+	if t.metrics != nil {
+		t.metrics.TokensProduced(len(tokens))
+	}
+	return tokens
+}
+
+// TokenFrequencies tokenizes text like Tokenize but, instead of
+// deduplicating, counts how many words each token was produced from.
+// A token emitted more than once for the same word, such as "haus" as both
+// lowercase original and segment, counts once for that word.
+func (t *Tokenizer) TokenFrequencies(text string) map[string]int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	counts := make(map[string]int)
+	wordTokens := make(tokenSet)
+	emit := func(token string, _ tokenOrigin) {
+		if wordTokens.add(token) {
+			counts[token]++
+		}
+	}
+
+	for _, raw := range t.splitWords(text) {
+		if raw.Type == TokenSeparator {
 			continue
 		}
+		clear(wordTokens)
+		t.tokenizeRaw(raw, emit)
+	}
+
+	return counts
+}
+
+// contextCheckInterval is how many words TokenizeContext processes between
+// checks for cancellation.
+const contextCheckInterval = 64
 
-		// Compound decomposition
-		segments := t.splitter.Split(raw.Text)
+// TokenizeContext is like Tokenize but stops early and returns ctx.Err() when
+// ctx is cancelled. Cancellation is checked every contextCheckInterval words.
+func (t *Tokenizer) TokenizeContext(ctx context.Context, text string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 
-		// Add lowercase original (preserves umlauts) if enabled
-		if t.includeLowercaseOriginal {
-			original := t.normalizer.LowercaseOnly(raw.Text)
-			if _, exists := resultSet[original]; !exists {
-				resultSet[original] = struct{}{}
-				results = append(results, original)
+	seen := make(tokenSet)
+	var results []string
+	emit := func(token string, _ tokenOrigin) {
+		if seen.add(token) {
+			results = append(results, token)
+		}
+	}
+
+	words := 0
+	for _, raw := range t.splitWords(text) {
+		if raw.Type == TokenSeparator {
+			continue
+		}
+		if words%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
 			}
 		}
+		words++
+		t.tokenizeRaw(raw, emit)
+	}
 
-		// Add normalized+stemmed segments
-		for _, seg := range segments {
-			normalized := t.normalizer.Normalize(seg)
-			if _, exists := resultSet[normalized]; !exists {
-				resultSet[normalized] = struct{}{}
-				results = append(results, normalized)
+	if t.metrics != nil {
+		t.metrics.TokensProduced(len(results))
+	}
+	return results, nil
+}
+
+// TokenizeTagged is like Tokenize but tags each token with the case of the
+// word it was first produced from. Tags are CaseUnknown unless EmitCaseTag
+// is enabled.
+func (t *Tokenizer) TokenizeTagged(text string) []TaggedToken {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(tokenSet)
+	var results []TaggedToken
+
+	for _, raw := range t.splitWords(text) {
+		if raw.Type == TokenSeparator {
+			continue
+		}
+
+		tag := CaseUnknown
+		if t.emitCaseTag {
+			tag = DetectCase(raw.Text)
+		}
+		t.tokenizeRaw(raw, func(token string, _ tokenOrigin) {
+			if seen.add(token) {
+				results = append(results, TaggedToken{Text: token, Case: tag})
+			}
+		})
+	}
+
+	return results
+}
+
+// TokenMeta is an output token with properties useful as features downstream.
+type TokenMeta struct {
+	Text              string
+	Kind              TokenKind
+	IsCompoundSegment bool // Derived from a segment of a word that split into several
+	InDictionary      bool // The word or segment it came from is a dictionary entry
+	WasStemmed        bool // Stemming or lemmatization changed the normalized form
+}
+
+// TokenizeMeta is like Tokenize but annotates each token with metadata.
+// Like Tokenize, tokens are deduplicated; each keeps the metadata of the
+// occurrence that first produced it.
+func (t *Tokenizer) TokenizeMeta(text string) []TokenMeta {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(tokenSet)
+	var results []TokenMeta
+
+	for _, raw := range t.splitWords(text) {
+		t.tokenizeRaw(raw, func(token string, origin tokenOrigin) {
+			if !seen.add(token) {
+				return
 			}
+			results = append(results, TokenMeta{
+				Text:              token,
+				Kind:              origin.kind,
+				IsCompoundSegment: origin.segment,
+				InDictionary:      origin.source != "" && t.splitter.isWordInDict(origin.source),
+				WasStemmed:        origin.normalized && t.unstemmedNormalizer.Normalize(origin.source) != token,
+			})
+		})
+	}
+
+	return results
+}
+
+// TokenGroup is a source word together with the tokens Tokenize produces
+// for it: its normalized segments, plus the lowercase original and other
+// forms where enabled.
+type TokenGroup struct {
+	Original string
+	Segments []string
+}
+
+// TokenizeGrouped splits text into words and returns one group per word, in
+// input order, relating each compound to the segments it decomposed into.
+// Groups hold the same tokens as Tokenize, with stop words, token filters
+// and output case applied, so words that produce no tokens have no group.
+// Unlike Tokenize, tokens are deduplicated within each group only.
+func (t *Tokenizer) TokenizeGrouped(text string) []TokenGroup {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var groups []TokenGroup
+	var tokens []string
+	wordTokens := make(tokenSet)
+	emit := func(token string, _ tokenOrigin) {
+		if wordTokens.add(token) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, raw := range t.splitWords(text) {
+		if raw.Type == TokenSeparator {
+			continue
+		}
+		clear(wordTokens)
+		tokens = nil
+		t.tokenizeRaw(raw, emit)
+		if len(tokens) > 0 {
+			groups = append(groups, TokenGroup{Original: raw.Text, Segments: tokens})
+		}
+	}
+
+	return groups
+}
+
+// Decompound returns the compound segments of each word in text, in input
+// order, without normalization or deduplication. Segments keep the source
+// word's spelling and case: "Brandschutz Brandschutz" → [[Brand schutz]
+// [Brand schutz]]. Words that don't split have a single segment.
+func (t *Tokenizer) Decompound(text string) [][]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var result [][]string
+	for _, raw := range t.splitWords(text) {
+		if raw.Type != TokenWord {
+			continue
 		}
+		result = append(result, t.sourceSegments(raw.Text, t.split(raw.Text)))
 	}
+	return result
+}
+
+// sourceSegments cuts word into the pieces that t.split's segments came
+// from, so the splitter's lowercased (and, with NormalizeBeforeSplit,
+// umlaut-folded) segments map back to the source spelling. If they can't
+// be mapped back, segments are returned as a copy.
+func (t *Tokenizer) sourceSegments(word string, segments []string) []string {
+	spans := t.sourceSpans(word, segments)
+	if spans == nil {
+		return append([]string(nil), segments...)
+	}
+	runes := []rune(word)
+	pieces := make([]string, len(segments))
+	for i, s := range spans {
+		pieces[i] = string(runes[s.start:s.end])
+	}
+	return pieces
+}
+
+// tokenSet deduplicates emitted tokens.
+type tokenSet map[string]struct{}
+
+// maxPooledTokenSet is the largest token set returned to tokenSetPool.
+// Maps never shrink, so sets grown by very long texts are dropped instead.
+const maxPooledTokenSet = 1024
+
+// tokenSetPool recycles token sets between Tokenize calls.
+var tokenSetPool = sync.Pool{
+	New: func() any { return make(tokenSet) },
+}
+
+// getTokenSet returns an empty token set from the pool.
+func getTokenSet() tokenSet {
+	return tokenSetPool.Get().(tokenSet)
+}
+
+// putTokenSet empties s and returns it to the pool.
+func putTokenSet(s tokenSet) {
+	if len(s) > maxPooledTokenSet {
+		return
+	}
+	clear(s)
+	tokenSetPool.Put(s)
+}
+
+// add records token and reports whether it was new.
+func (s tokenSet) add(token string) bool {
+	if _, exists := s[token]; exists {
+		return false
+	}
+	s[token] = struct{}{}
+	return true
+}
+
+// TokenizeRaw returns the full stream of word and separator tokens before
+// splitting and normalization. Concatenating the Text of all returned tokens
+// reproduces the input exactly. Configured abbreviations and German numbers
+// are kept whole.
+func (t *Tokenizer) TokenizeRaw(text string) []RawToken {
+	return SplitWordsWithOptions(text, t.splitOptions)
+}
+
+// TokenizeBatch tokenizes each text in parallel over a bounded worker pool.
+// result[i] holds the tokens of texts[i], identical to Tokenize(texts[i]).
+func (t *Tokenizer) TokenizeBatch(texts []string) [][]string {
+	results := make([][]string, len(texts))
+
+	workers := t.batchWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(texts) {
+		workers = len(texts)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = t.Tokenize(texts[i])
+			}
+		}()
+	}
+
+	for i := range texts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
 	return results
 }
 
+// TokenizeValidated is like Tokenize but returns ErrInvalidUTF8 instead of
+// tokenizing input containing invalid UTF-8 byte sequences.
+func (t *Tokenizer) TokenizeValidated(text string) ([]string, error) {
+	if !utf8.ValidString(text) {
+		return nil, ErrInvalidUTF8
+	}
+	return t.Tokenize(text), nil
+}
+
+// splitWords splits text into raw tokens for tokenization. Format characters
+// are removed first when enabled so a soft hyphen doesn't split a word.
+// Joiners kept inside words are removed from them, and elided compound stubs
+// are expanded afterwards when enabled.
+func (t *Tokenizer) splitWords(text string) []RawToken {
+	if t.stripFormatChars {
+		text = RemoveFormatChars(text)
+	}
+	raws := SplitWordsWithOptions(text, t.splitOptions)
+	if t.splitOptions.Joiners {
+		for i := range raws {
+			if raws[i].Type == TokenWord {
+				raws[i].Text = StripJoiners(raws[i].Text)
+			}
+		}
+	}
+	if t.expandElisions {
+		raws = t.expandElidedStubs(raws)
+	}
+	return raws
+}
+
+// split decomposes word, recursively if RecursiveSplit is enabled. With
+// NormalizeBeforeSplit, umlauts and ß are folded first. With
+// DigitBoundaries, letter/digit transitions split the word first and only
+// the letter runs are decomposed.
+func (t *Tokenizer) split(word string) []string {
+	if t.normalizeBeforeSplit {
+		word = normalizeUmlauts(word)
+	}
+	if t.digitBoundaries {
+		if runs := splitAtDigits(word); len(runs) > 1 {
+			var segments []string
+			for _, run := range runs {
+				if r, _ := utf8.DecodeRuneInString(run); unicode.IsDigit(r) {
+					segments = append(segments, run)
+				} else {
+					segments = append(segments, t.splitWord(run)...)
+				}
+			}
+			return segments
+		}
+	}
+	return t.splitWord(word)
+}
+
+// splitWord decomposes word with the compound splitter.
+func (t *Tokenizer) splitWord(word string) []string {
+	if t.recursiveSplit {
+		return t.splitter.SplitRecursive(word)
+	}
+	return t.splitter.Split(word)
+}
+
+// unresolved reports whether segments is a single word that didn't split
+// and doesn't match the dictionary.
+func (t *Tokenizer) unresolved(segments []string) bool {
+	return len(segments) == 1 && !t.splitter.isValidWord(segments[0])
+}
+
+// tokenOrigin records where tokenizeWord derived a token from, for
+// TokenizeMeta and TokenizeWithSpans.
+type tokenOrigin struct {
+	kind       TokenKind // How the token was produced
+	source     string    // Word or segment the token was derived from, empty for n-grams
+	segment    bool      // source is a segment of a word that split into several
+	normalized bool      // Produced by the main normalizer, so stemming may have applied
+	span       span      // Runes of the source word the token was derived from
+}
+
+// span is a rune range within a word.
+type span struct {
+	start, end int
+}
+
+// sourceSpans returns the rune span within word of each segment t.split
+// produced for it, or nil if they can't be mapped back. With
+// NormalizeBeforeSplit, spans are computed on the folded word and mapped
+// back through its rune offsets, so "Fußball" split as [fuss ball] spans
+// "Fuß" and "ball".
+func (t *Tokenizer) sourceSpans(word string, segments []string) []span {
+	if !t.normalizeBeforeSplit {
+		return segmentSpans(word, segments)
+	}
+	folded, offsets := foldUmlauts(word)
+	spans := segmentSpans(folded, segments)
+	for i, s := range spans {
+		spans[i] = span{offsets[s.start], offsets[s.end]}
+	}
+	return spans
+}
+
+// foldUmlauts applies normalizeUmlauts to word and also returns, for each
+// rune offset in the folded word and its end, the offset in word it came
+// from. Both runes of "ss" folded from ß map to the ß.
+func foldUmlauts(word string) (string, []int) {
+	var b strings.Builder
+	offsets := make([]int, 0, len(word)+1)
+	i := 0
+	for _, r := range word {
+		if repl, ok := umlautFolds[r]; ok {
+			b.WriteString(repl)
+			for range utf8.RuneCountInString(repl) {
+				offsets = append(offsets, i)
+			}
+		} else {
+			b.WriteRune(r)
+			offsets = append(offsets, i)
+		}
+		i++
+	}
+	return b.String(), append(offsets, i)
+}
+
+// segmentSpans returns the rune span of each segment within word, or nil if
+// the segments don't add up to word's length, as when lowercasing or a
+// confusable correction changed it.
+func segmentSpans(word string, segments []string) []span {
+	spans := make([]span, len(segments))
+	pos := 0
+	for i, seg := range segments {
+		n := utf8.RuneCountInString(seg)
+		spans[i] = span{pos, pos + n}
+		pos += n
+	}
+	if pos != utf8.RuneCountInString(word) {
+		return nil
+	}
+	return spans
+}
+
+// isStopWord reports whether the lowercased word is in the stop-word set.
+func (t *Tokenizer) isStopWord(word string) bool {
+	_, ok := t.stopWords[t.normalizer.LowercaseOnly(word)]
+	return ok
+}
+
+// withoutStopWords returns segments minus stop words, and their spans if
+// spans is non-nil. The input may be shared with the split cache, so a
+// filtered copy is returned instead of modifying it.
+func (t *Tokenizer) withoutStopWords(segments []string, spans []span) ([]string, []span) {
+	var kept []string
+	var keptSpans []span
+	for i, seg := range segments {
+		if !t.isStopWord(seg) {
+			if kept != nil {
+				kept = append(kept, seg)
+				if spans != nil {
+					keptSpans = append(keptSpans, spans[i])
+				}
+			}
+			continue
+		}
+		if kept == nil {
+			kept = append(make([]string, 0, len(segments)-1), segments[:i]...)
+			if spans != nil {
+				keptSpans = append(make([]span, 0, len(segments)-1), spans[:i]...)
+			}
+		}
+	}
+	if kept == nil {
+		return segments, spans
+	}
+	return kept, keptSpans
+}
+
+// tokenizeRaw emits the output tokens of a word or symbol token. Separators
+// produce none.
+func (t *Tokenizer) tokenizeRaw(raw RawToken, emit func(string, tokenOrigin)) {
+	switch raw.Type {
+	case TokenWord:
+		t.tokenizeWord(raw.Text, emit)
+	case TokenSymbol:
+		t.tokenizeSymbol(raw.Text, emit)
+	}
+}
+
+// tokenizeSymbol emits a symbol token as is.
+func (t *Tokenizer) tokenizeSymbol(symbol string, emit func(string, tokenOrigin)) {
+	if t.tokenFilter != nil {
+		emit = t.filtered(emit)
+	}
+	emit(symbol, tokenOrigin{kind: KindSymbol, span: span{0, utf8.RuneCountInString(symbol)}})
+}
+
+// normalizerFor returns the normalizer for the tokens of word: the
+// unstemmed one if StemNounsOnly is enabled and word doesn't look like a
+// noun.
+func (t *Tokenizer) normalizerFor(word string) *Normalizer {
+	if t.stemNounsOnly && !looksLikeNoun(word) {
+		return t.unstemmedNormalizer
+	}
+	return t.normalizer
+}
+
+// looksLikeNoun reports whether word is capitalized, as German nouns are,
+// and isn't a function word capitalized at the start of a sentence.
+func looksLikeNoun(word string) bool {
+	if DetectCase(word) == CaseLower {
+		return false
+	}
+	_, function := germanStopWordSet[strings.ToLower(word)]
+	return !function
+}
+
+// filtered wraps emit to pass tokens through the TokenFilter first.
+func (t *Tokenizer) filtered(emit func(string, tokenOrigin)) func(string, tokenOrigin) {
+	return func(token string, origin tokenOrigin) {
+		if token, keep := t.tokenFilter(token); keep {
+			emit(token, origin)
+		}
+	}
+}
+
+// recased wraps emit to apply OutputCase to the tokens of word first.
+func (t *Tokenizer) recased(word string, emit func(string, tokenOrigin)) func(string, tokenOrigin) {
+	runes := []rune(word)
+	return func(token string, origin tokenOrigin) {
+		source := word
+		if s := origin.span; s.end <= len(runes) {
+			source = string(runes[s.start:s.end])
+		}
+		emit(applyOutputCase(t.outputCase, token, source), origin)
+	}
+}
+
+// tokenizeWord emits all output tokens for a single word.
+func (t *Tokenizer) tokenizeWord(word string, emit func(string, tokenOrigin)) {
+	if t.tokenFilter != nil {
+		emit = t.filtered(emit)
+	}
+	if t.outputCase != OutputLower {
+		emit = t.recased(word, emit)
+	}
+	whole := span{0, utf8.RuneCountInString(word)}
+
+	// Pass non-Latin words through without German-specific processing
+	if t.passThroughNonLatin && !isMostlyLatin(word) {
+		emit(t.normalizer.LowercaseOnly(word), tokenOrigin{kind: KindOriginal, source: word, span: whole})
+		return
+	}
+
+	// Compound decomposition
+	segments := t.split(word)
+	original := word
+	normalizer := t.normalizerFor(original)
+
+	// Spelling of the lowercase original: the source word, with historical
+	// spelling modernized but not OCR-corrected
+	spelling := word
+
+	// Retry OCR-corrupted words with confusables corrected if enabled
+	if t.confusables != nil && t.unresolved(segments) {
+		for _, candidate := range t.confusables.candidates(word) {
+			if split := t.split(candidate); !t.unresolved(split) {
+				word, segments = candidate, split
+				break
+			}
+		}
+	}
+	// Retry old hyphenation artifacts with modern spelling if enabled
+	if t.historicalSpelling && t.unresolved(segments) {
+		if candidate := NormalizeHistoricalSpelling(word); candidate != word {
+			if split := t.split(candidate); !t.unresolved(split) {
+				word, segments = candidate, split
+				spelling = NormalizeHistoricalSpelling(spelling)
+			}
+		}
+	}
+	if t.splitThreshold > 0 && len(segments) > 1 {
+		if confidence := t.splitter.Confidence(segments); confidence < t.splitThreshold {
+			t.logger.Debug("split below threshold", "word", word, "confidence", confidence)
+			segments = []string{word}
+		}
+	}
+	compound := len(segments) > 1
+	if !compound && (t.metrics != nil || t.logger.Enabled(context.Background(), slog.LevelDebug)) && t.unresolved(segments) {
+		if t.metrics != nil {
+			t.metrics.SplitFallback()
+		}
+		t.logger.Debug("word not split", "word", word)
+	}
+
+	// Segment spans within the original word; whole-word spans if they can't
+	// be mapped back
+	var spans []span
+	if compound {
+		spans = t.sourceSpans(original, segments)
+	}
+	segmentSpan := func(i int) span {
+		if spans == nil {
+			return whole
+		}
+		return spans[i]
+	}
+
+	// Drop stop words, and stop-word segments of compounds
+	if t.stopWords != nil {
+		if t.isStopWord(word) {
+			return
+		}
+		segments, spans = t.withoutStopWords(segments, spans)
+	}
+
+	// Add lowercase original (preserves umlauts) if enabled. It keeps the
+	// source spelling even if an OCR-corrected form was split. With
+	// DedupAcrossForms, it is a duplicate of the word's normalized form
+	// ("größe" of "grosse") whenever that is emitted: as the only segment
+	// of an unsplit word, or as the whole compound with AlwaysEmitWhole.
+	if t.includeLowercaseOriginal && !(t.dedupAcrossForms && (!compound || t.alwaysEmitWhole)) {
+		emit(t.normalizer.LowercaseOnly(spelling), tokenOrigin{kind: KindOriginal, source: spelling, span: whole})
+	}
+
+	// Add the normalized whole compound if enabled; unsplit words are
+	// emitted as their only segment below
+	if t.alwaysEmitWhole && compound {
+		emit(normalizer.Normalize(word), tokenOrigin{kind: KindWhole, source: word, normalized: true, span: whole})
+	}
+
+	// Add normalized+stemmed segments, or the source spelling of compound
+	// segments with SegmentNormalization. Unsplit words are their only
+	// segment and stay normalized.
+	if t.segmentNormalization == SegmentFull || !compound {
+		for i, seg := range segments {
+			emit(normalizer.Normalize(seg), tokenOrigin{kind: KindSegment, source: seg, segment: compound, normalized: true, span: segmentSpan(i)})
+		}
+	} else {
+		runes := []rune(original)
+		for i, seg := range segments {
+			source := seg
+			if spans != nil {
+				source = string(runes[spans[i].start:spans[i].end])
+			}
+			if t.segmentNormalization == SegmentLowercaseOnly {
+				source = t.normalizer.LowercaseOnly(source)
+			}
+			emit(source, tokenOrigin{kind: KindSegment, source: seg, segment: compound, span: segmentSpan(i)})
+		}
+	}
+
+	// Add the other ß/ss form of segments containing ß if enabled
+	if t.eszettNormalizer != nil {
+		for i, seg := range segments {
+			if strings.ContainsAny(seg, "ß\u1E9E") {
+				emit(t.eszettNormalizer.Normalize(seg), tokenOrigin{kind: KindVariant, source: seg, segment: compound, span: segmentSpan(i)})
+			}
+		}
+	}
+
+	// Add character n-grams for out-of-vocabulary words that didn't split
+	if t.subwordFallback && t.unresolved(segments) {
+		for _, gram := range charNgrams(normalizer.Normalize(segments[0]), t.ngramMin, t.ngramMax) {
+			emit(gram, tokenOrigin{kind: KindNgram, span: whole})
+		}
+	}
+
+	// Add umlaut-preserving, umlaut-stripped and digraph forms if enabled
+	if t.emitVariants {
+		for _, v := range umlautVariants(t.normalizer.LowercaseOnly(word)) {
+			emit(v, tokenOrigin{kind: KindVariant, source: word, span: whole})
+		}
+		for i, seg := range segments {
+			for _, v := range umlautVariants(seg) {
+				emit(v, tokenOrigin{kind: KindVariant, source: seg, segment: compound, span: segmentSpan(i)})
+			}
+		}
+	}
+}
+
+// charNgrams returns the character n-grams of word wrapped in boundary
+// markers, for sizes minN through maxN: "haus" with 3..3 → ["<ha" "hau" "aus" "us>"].
+func charNgrams(word string, minN, maxN int) []string {
+	runes := make([]rune, 0, len(word)+2)
+	runes = append(runes, ngramStart)
+	runes = append(runes, []rune(word)...)
+	runes = append(runes, ngramEnd)
+
+	var grams []string
+	for n := minN; n <= maxN && n <= len(runes); n++ {
+		for i := 0; i+n <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+n]))
+		}
+	}
+	return grams
+}
+
+// orDefault returns v, or def if v is zero.
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+// umlautVariants returns the umlaut-preserving, umlaut-stripped and digraph
+// spellings of a lowercase word: "wärme" → ["wärme", "warme", "waerme"].
+// Returns nil if the word contains no umlaut or ß.
+func umlautVariants(word string) []string {
+	stripped := normalizeUmlauts(word)
+	if stripped == word {
+		return nil
+	}
+	return []string{word, stripped, ConvertEszett(UmlautToDigraph(word))}
+}
+
+// NormalizeTrace runs s through the tokenizer's normalization pipeline and
+// returns the output of each step, for debugging.
+func (t *Tokenizer) NormalizeTrace(s string) []StepResult {
+	return t.normalizer.NormalizeTrace(s)
+}
+
+// SameToken reports whether a and b normalize to the same token under the
+// tokenizer's normalization pipeline, so a query term matches an indexed
+// one: "Größe" and "grösse" collide under DefaultConfig. Like tokens from
+// Tokenize, StemNounsOnly leaves words that don't look like nouns
+// unstemmed. Compound splitting is not applied.
+func (t *Tokenizer) SameToken(a, b string) bool {
+	return t.normalizerFor(a).Normalize(a) == t.normalizerFor(b).Normalize(b)
+}
+
 // AddWord adds a word to the dictionary.
-// Rebuilds FST immediately and persists to disk.
+// The word is visible to Tokenize immediately; the FST rebuild and write to
+// disk are deferred until FlushDictionary or Close.
 func (t *Tokenizer) AddWord(word string) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.dict.AddWord(word)
 }
 
 // RemoveWord removes a word from the dictionary.
-// Rebuilds FST immediately and persists to disk.
+// The rebuild is deferred like AddWord.
 func (t *Tokenizer) RemoveWord(word string) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.dict.RemoveWord(word)
 }
 
+// FlushDictionary rebuilds the FST and persists pending AddWord/RemoveWord
+// changes. It is a no-op when the dictionary is not dirty.
+func (t *Tokenizer) FlushDictionary() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.dict.Flush()
+}
+
+// ReloadDictionary replaces the dictionary with the one at dictPath.
+// The new dictionary is loaded before the old one is closed, and the swap
+// happens under the tokenizer lock, so concurrent Tokenize calls see either
+// the old or the new word set, never a mix. The split cache starts empty.
+// On error the current dictionary is kept.
+func (t *Tokenizer) ReloadDictionary(dictPath string) error {
+	dict, err := openDictionary(dictPath, t.bloomFilter, t.logger)
+	if err != nil {
+		t.logger.Error("dictionary reload failed", "path", dictPath, "error", err)
+		return err
+	}
+	t.logger.Info("dictionary reloaded", "path", dictPath, "words", dict.WordCount())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err = t.closeDictionary()
+	t.dict, t.release = dict, nil
+	t.splitter = t.splitter.withDictionary(dict)
+	return err
+}
+
+// WatchDictionary reloads the dictionary whenever its text file changes on
+// disk. It blocks until ctx is cancelled; run it in its own goroutine.
+// It watches the dictionary loaded at call time and stops when that
+// dictionary is closed, so restart it after ReloadDictionary.
+func (t *Tokenizer) WatchDictionary(ctx context.Context) error {
+	t.mu.RLock()
+	dict := t.dict
+	t.mu.RUnlock()
+	return dict.WatchFile(ctx)
+}
+
 // Close releases resources (call when done with tokenizer).
+// A dictionary shared through SharedTokenizer is closed once its last
+// tokenizer is.
 func (t *Tokenizer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closeDictionary()
+}
+
+// closeDictionary closes the current dictionary, or releases it if it is
+// shared. Callers must hold t.mu.
+func (t *Tokenizer) closeDictionary() error {
+	if t.release != nil {
+		return t.release()
+	}
 	return t.dict.Close()
 }
 
 // DictionaryWordCount returns the number of words in the dictionary.
 func (t *Tokenizer) DictionaryWordCount() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.dict.WordCount()
 }
 
+// Classify reports whether word is a compound, a single dictionary
+// component or unknown; see CompoundSplitter.Classify.
+func (t *Tokenizer) Classify(word string) WordClass {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.splitter.Classify(word)
+}
+
 // CacheSize returns the number of cached compound splits.
 func (t *Tokenizer) CacheSize() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.splitter.CacheSize()
 }
 
 // ClearCache clears the compound splitting cache.
 func (t *Tokenizer) ClearCache() {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	t.splitter.ClearCache()
 }
 
+// ExportCache writes the compound split cache to w; see
+// CompoundSplitter.ExportCache for the format.
+func (t *Tokenizer) ExportCache(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.splitter.ExportCache(w)
+}
+
+// ImportCache loads splits written by ExportCache into the compound split
+// cache. It returns ErrCacheDisabled if the tokenizer has no cache.
+func (t *Tokenizer) ImportCache(r io.Reader) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.splitter.ImportCache(r)
+}
+
+// CacheStats returns compound split cache counters. Counters restart when
+// the dictionary is replaced with ReloadDictionary.
+func (t *Tokenizer) CacheStats() CacheStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.splitter.CacheStats()
+}
+
 // CacheEnabled returns true if caching is enabled.
 func (t *Tokenizer) CacheEnabled() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return t.splitter.CacheEnabled()
 }
 
@@ -1,9 +1,17 @@
 package tokenizer
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"unicode/utf8"
 )
 
 func getTestDictPath() string {
@@ -28,6 +36,7 @@ func getTestDictPath() string {
 func testConfig() Config {
 	return Config{
 		Cache:             true,
+		CacheSize:         CacheSize,
 		LowercaseOriginal: true,
 		Normalizers: NormalizerConfig{
 			NFKDDecompose:        true,
@@ -145,6 +154,7 @@ func TestTokenizer_WithCustomNormalizer(t *testing.T) {
 	// Create tokenizer with custom normalizer config (without stemming)
 	tok, err := NewTokenizer(dictPath, Config{
 		Cache:             true,
+		CacheSize:         CacheSize,
 		LowercaseOriginal: true,
 		Normalizers: NormalizerConfig{
 			NFKDDecompose:        true,
@@ -247,3 +257,1473 @@ func TestTokenizer_DictionaryWordCount(t *testing.T) {
 		t.Errorf("Expected at least 1000 words in dictionary, got %d", count)
 	}
 }
+
+func TestTokenizer_CustomNormalizerStep(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.Normalizers.StemGerman = false
+	cfg.Normalizers.Custom = map[string]NormalizerFunc{
+		"phonetic-ph": func(s string) string { return strings.ReplaceAll(s, "ph", "f") },
+	}
+	cfg.Normalizers.CustomOrder = []string{"phonetic-ph"}
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Philosophie")
+	resultSet := make(map[string]bool)
+	for _, tok := range result {
+		resultSet[tok] = true
+	}
+
+	if !resultSet["filosofie"] {
+		t.Errorf("Expected custom step output 'filosofie' in result, got %v", result)
+	}
+}
+
+func TestTokenizer_CustomNormalizerStepPosition(t *testing.T) {
+	upper := func(s string) string { return strings.ToUpper(s) }
+
+	tests := []struct {
+		position StepPosition
+		expected string
+	}{
+		{CustomFirst, "haus"},      // Lowercase runs afterwards
+		{CustomBeforeStem, "HAUS"}, // Stemming is disabled, nothing runs afterwards
+		{CustomLast, "HAUS"},
+	}
+
+	for _, tt := range tests {
+		nc := NormalizerConfig{
+			Lowercase:      true,
+			Custom:         map[string]NormalizerFunc{"upper": upper},
+			CustomOrder:    []string{"upper"},
+			CustomPosition: tt.position,
+		}
+		n, err := nc.buildNormalizer()
+		if err != nil {
+			t.Fatalf("buildNormalizer() error: %v", err)
+		}
+		if result := n.Normalize("Haus"); result != tt.expected {
+			t.Errorf("position %d: Normalize(%q) = %q, want %q", tt.position, "Haus", result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_UnknownCustomNormalizerStep(t *testing.T) {
+	cfg := testConfig()
+	cfg.Normalizers.CustomOrder = []string{"missing"}
+
+	if _, err := NewTokenizer(getTestDictPath(), cfg); err == nil {
+		t.Error("Expected error for unknown custom step name")
+	}
+}
+
+func TestSwissNormalizerConfig(t *testing.T) {
+	n, err := SwissNormalizerConfig().buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Größe", "grösse"},
+		{"Straße", "strasse"},
+		{"weiß", "weiss"},
+		{"Wärme", "wärme"},
+	}
+
+	for _, tt := range tests {
+		result := n.Normalize(tt.input)
+		if result != tt.expected {
+			t.Errorf("Swiss Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_EmitVariants(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.EmitVariants = true
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Wärme")
+	resultSet := make(map[string]bool)
+	for _, tok := range result {
+		resultSet[tok] = true
+	}
+
+	for _, expected := range []string{"wärme", "warme", "waerme"} {
+		if !resultSet[expected] {
+			t.Errorf("Tokenize(%q) missing variant %q, got %v", "Wärme", expected, result)
+		}
+	}
+
+	// Plain ASCII words produce no extra variants
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+
+	withVariants := tok.Tokenize("Haus")
+	without := plain.Tokenize("Haus")
+	if len(withVariants) != len(without) {
+		t.Errorf("Tokenize(%q) with variants = %v, want %v", "Haus", withVariants, without)
+	}
+}
+
+func TestNormalizerConfig_MinStemLength(t *testing.T) {
+	// Stub stemmer that drops a trailing "e" or "en", so the guard is observable
+	stem := func(s string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(s, "n"), "e")
+	}
+
+	n, err := NormalizerConfig{
+		Lowercase:     true,
+		StemGerman:    true,
+		Stemmer:       stem,
+		MinStemLength: 6,
+	}.buildNormalizer()
+	if err != nil {
+		t.Fatalf("buildNormalizer() error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Decke", "decke"},     // 5 runes: below threshold, unstemmed
+		{"Arbeiten", "arbeit"}, // 8 runes: stemmed
+		{"Größe", "größe"},     // rune length, not byte length
+	}
+
+	for _, tt := range tests {
+		result := n.Normalize(tt.input)
+		if result != tt.expected {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_ConcurrentTokenizeAndAddWord(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "wärme", "dämmung")
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				tok.Tokenize("Brandschutzkonzept und Wärmedämmung")
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, word := range []string{"konzept", "haus", "decke"} {
+			if err := tok.AddWord(word); err != nil {
+				t.Errorf("AddWord(%q) error: %v", word, err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	// After all updates, tokenization must reflect the new word set
+	result := tok.Tokenize("Brandschutzkonzept")
+	resultSet := make(map[string]bool)
+	for _, tok := range result {
+		resultSet[tok] = true
+	}
+	if !resultSet["konzept"] {
+		t.Errorf("Expected 'konzept' after concurrent AddWord, got %v", result)
+	}
+}
+
+func TestTokenizer_TokenizeValidated(t *testing.T) {
+	dictPath := getTestDictPath()
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	if _, err := tok.TokenizeValidated("Brand\xff\xfeschutz"); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("TokenizeValidated(invalid) error = %v, want %v", err, ErrInvalidUTF8)
+	}
+
+	result, err := tok.TokenizeValidated("Brandschutzkonzept")
+	if err != nil {
+		t.Fatalf("TokenizeValidated(valid) error: %v", err)
+	}
+	expected := tok.Tokenize("Brandschutzkonzept")
+	if strings.Join(result, " ") != strings.Join(expected, " ") {
+		t.Errorf("TokenizeValidated() = %v, want %v", result, expected)
+	}
+}
+
+func TestTokenizer_ReloadDictionary(t *testing.T) {
+	first := newTestDictionary(t, "brand", "schutz", "konzept")
+	second := newTestDictionary(t, "stahl", "beton", "decke")
+
+	tok, err := NewTokenizer(first, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	contains := func(tokens []string, want string) bool {
+		for _, token := range tokens {
+			if token == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	if result := tok.Tokenize("Brandschutzkonzept"); !contains(result, "schutz") {
+		t.Fatalf("Expected 'schutz' with first dictionary, got %v", result)
+	}
+
+	if err := tok.ReloadDictionary(second); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+
+	if tok.CacheSize() != 0 {
+		t.Errorf("Expected empty cache after reload, got %d entries", tok.CacheSize())
+	}
+	if result := tok.Tokenize("Brandschutzkonzept"); contains(result, "schutz") {
+		t.Errorf("Expected no 'schutz' after reload, got %v", result)
+	}
+	if result := tok.Tokenize("Stahlbetondecke"); !contains(result, "beton") {
+		t.Errorf("Expected 'beton' after reload, got %v", result)
+	}
+
+	// A failed reload keeps the current dictionary
+	if err := tok.ReloadDictionary(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error reloading a missing dictionary")
+	}
+	if result := tok.Tokenize("Stahlbetondecke"); !contains(result, "beton") {
+		t.Errorf("Expected 'beton' after failed reload, got %v", result)
+	}
+}
+
+func TestTokenizer_SubwordFallback(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.SubwordFallback = true
+	cfg.NgramMin = 3
+	cfg.NgramMax = 3
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	// Unknown, unsplittable word gets boundary-marked n-grams
+	result := tok.Tokenize("Xyzqu")
+	resultSet := make(map[string]bool)
+	for _, tok := range result {
+		resultSet[tok] = true
+	}
+	for _, expected := range []string{"<xy", "xyz", "yzq", "zqu", "qu>"} {
+		if !resultSet[expected] {
+			t.Errorf("Tokenize(%q) missing n-gram %q, got %v", "Xyzqu", expected, result)
+		}
+	}
+
+	// Dictionary words and splittable compounds get no n-grams
+	for _, input := range []string{"Haus", "Brandschutzkonzept"} {
+		for _, token := range tok.Tokenize(input) {
+			if strings.ContainsAny(token, "<>") {
+				t.Errorf("Tokenize(%q) emitted n-gram %q for known word", input, token)
+			}
+		}
+	}
+}
+
+func TestCharNgrams(t *testing.T) {
+	result := charNgrams("haus", 3, 4)
+	expected := []string{"<ha", "hau", "aus", "us>", "<hau", "haus", "aus>"}
+	if strings.Join(result, " ") != strings.Join(expected, " ") {
+		t.Errorf("charNgrams(%q, 3, 4) = %v, want %v", "haus", result, expected)
+	}
+}
+
+func TestTokenizer_TokenizeBatch(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.BatchWorkers = 3
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	texts := []string{
+		"Brandschutzkonzept",
+		"Der fährt",
+		"",
+		"Wärmedämmung der Stahlbetondecke",
+		"Haus",
+		"Größe",
+		"Brandschutzkonzept",
+	}
+
+	results := tok.TokenizeBatch(texts)
+	if len(results) != len(texts) {
+		t.Fatalf("TokenizeBatch returned %d results, want %d", len(results), len(texts))
+	}
+	for i, text := range texts {
+		expected := tok.Tokenize(text)
+		if strings.Join(results[i], " ") != strings.Join(expected, " ") {
+			t.Errorf("TokenizeBatch[%d] (%q) = %v, want %v", i, text, results[i], expected)
+		}
+	}
+
+	if results := tok.TokenizeBatch(nil); len(results) != 0 {
+		t.Errorf("TokenizeBatch(nil) = %v, want empty", results)
+	}
+}
+
+func TestTokenizer_TokenizeRaw(t *testing.T) {
+	dictPath := getTestDictPath()
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	inputs := []string{
+		"Der Brandschutzkonzept, und die Wärmedämmung!",
+		"  führende  Leerzeichen\tund\nZeilenumbrüche  ",
+		"„Zitat“ – 123abc…",
+		"",
+	}
+
+	for _, input := range inputs {
+		var rebuilt strings.Builder
+		for _, raw := range tok.TokenizeRaw(input) {
+			rebuilt.WriteString(raw.Text)
+		}
+		if rebuilt.String() != input {
+			t.Errorf("TokenizeRaw(%q) reconstructs to %q", input, rebuilt.String())
+		}
+	}
+
+	raw := tok.TokenizeRaw("Haus, Tür")
+	if len(raw) != 3 || raw[1].Type != TokenSeparator || raw[1].Text != ", " {
+		t.Errorf("TokenizeRaw(%q) = %+v, want word, separator, word", "Haus, Tür", raw)
+	}
+}
+
+func TestTokenizer_TokenizeTagged(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.EmitCaseTag = true
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tags := make(map[string]CaseTag)
+	for _, tagged := range tok.TokenizeTagged("Haus und Brandschutzkonzept") {
+		tags[tagged.Text] = tagged.Case
+	}
+
+	expected := map[string]CaseTag{
+		"haus":   CaseCapitalized,
+		"und":    CaseLower,
+		"brand":  CaseCapitalized, // Segments inherit the tag of their compound
+		"schutz": CaseCapitalized,
+	}
+	for token, tag := range expected {
+		if tags[token] != tag {
+			t.Errorf("TokenizeTagged tag for %q = %v, want %v", token, tags[token], tag)
+		}
+	}
+
+	// Without EmitCaseTag, tokens match Tokenize and are untagged
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+
+	tagged := plain.TokenizeTagged("Haus und")
+	tokens := plain.Tokenize("Haus und")
+	if len(tagged) != len(tokens) {
+		t.Fatalf("TokenizeTagged = %v, want tokens %v", tagged, tokens)
+	}
+	for i := range tagged {
+		if tagged[i].Text != tokens[i] || tagged[i].Case != CaseUnknown {
+			t.Errorf("TokenizeTagged[%d] = %+v, want {%s unknown}", i, tagged[i], tokens[i])
+		}
+	}
+}
+
+func TestTokenizer_TokenizeContext(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel from inside the pipeline once a few hundred words have been seen
+	calls := 0
+	cfg := testConfig()
+	cfg.Normalizers.Custom = map[string]NormalizerFunc{
+		"cancel": func(s string) string {
+			calls++
+			if calls == 300 {
+				cancel()
+			}
+			return s
+		},
+	}
+	cfg.Normalizers.CustomOrder = []string{"cancel"}
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	text := strings.Repeat("Haus Brandschutz Wärme ", 1000)
+
+	tokens, err := tok.TokenizeContext(ctx, text)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("TokenizeContext error = %v, want %v", err, context.Canceled)
+	}
+	if tokens != nil {
+		t.Errorf("TokenizeContext tokens = %v, want nil on cancellation", tokens)
+	}
+	if calls >= 3000 {
+		t.Errorf("TokenizeContext processed %d segments, expected to stop early", calls)
+	}
+
+	// An uncancelled context behaves like Tokenize
+	tokens, err = tok.TokenizeContext(context.Background(), "Haus Brandschutz")
+	if err != nil {
+		t.Fatalf("TokenizeContext error = %v", err)
+	}
+	expected := tok.Tokenize("Haus Brandschutz")
+	if strings.Join(tokens, ",") != strings.Join(expected, ",") {
+		t.Errorf("TokenizeContext = %v, want %v", tokens, expected)
+	}
+}
+
+func TestTokenizer_CacheSize(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.Cache = true
+	cfg.CacheSize = 2
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tok.Tokenize("Brandschutz Stahlbeton Wärmedämmung")
+	if tok.CacheSize() != 2 {
+		t.Errorf("CacheSize() = %d, want configured limit 2", tok.CacheSize())
+	}
+
+	// The limit survives a dictionary reload
+	if err := tok.ReloadDictionary(dictPath); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+	tok.Tokenize("Brandschutz Stahlbeton Wärmedämmung")
+	if tok.CacheSize() != 2 {
+		t.Errorf("CacheSize() after reload = %d, want 2", tok.CacheSize())
+	}
+}
+
+func TestTokenizer_RemoveFormatChars(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.Normalizers.RemoveFormatChars = true
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	for _, input := range []string{"Brand\u00ADschutz\u00ADkonzept", "Brand\u200Bschutzkonzept"} {
+		result := tok.Tokenize(input)
+		expected := tok.Tokenize("Brandschutzkonzept")
+		if strings.Join(result, ",") != strings.Join(expected, ",") {
+			t.Errorf("Tokenize(%q) = %v, want %v", input, result, expected)
+		}
+	}
+}
+
+func TestTokenizer_SplitThreshold(t *testing.T) {
+	cfg := testConfig()
+	cfg.SplitThreshold = 0.5
+	tok, err := NewTokenizer(newTestDictionary(t, "ei", "sen", "brand", "schutz"), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// "ei" + "sen" scores 0.25: kept whole
+		{"Eisen", []string{"eisen"}},
+		{"Brandschutz", []string{"brandschutz", "brand", "schutz"}},
+	}
+
+	for _, tt := range tests {
+		if result := tok.Tokenize(tt.input); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_PrefixFallback(t *testing.T) {
+	cfg := testConfig()
+	cfg.PrefixFallback = true
+	tok, err := NewTokenizer(newTestDictionary(t, "gehen"), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	if result, expected := tok.Tokenize("Vorgehen"), []string{"vorgehen", "vor", "gehen"}; !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) = %v, want %v", "Vorgehen", result, expected)
+	}
+}
+
+func TestTokenizer_TokenizeWords(t *testing.T) {
+	tok, err := NewTokenizer(newTestDictionary(t, "brand", "schutz"), testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	// Each element is one word, even with punctuation inside
+	words := []string{"Brandschutz", "z.B.", "", "Brand-Schutz", "brandschutz"}
+	expected := []string{"brandschutz", "brand", "schutz", "z.b.", "brand-schutz"}
+	if result := tok.TokenizeWords(words); !reflect.DeepEqual(result, expected) {
+		t.Errorf("TokenizeWords(%q) = %q, want %q", words, result, expected)
+	}
+
+	if result := tok.TokenizeWords(nil); result != nil {
+		t.Errorf("TokenizeWords(nil) = %q, want nil", result)
+	}
+}
+
+func TestTokenizer_Abbreviations(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.AbbreviationsPath = filepath.Join(filepath.Dir(dictPath), "german_abbreviations.txt")
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("z.B. ein Satz.")
+	resultSet := make(map[string]bool)
+	for _, tok := range result {
+		resultSet[tok] = true
+	}
+	if !resultSet["z.b."] {
+		t.Errorf("Tokenize(%q) = %v, want abbreviation token %q", "z.B. ein Satz.", result, "z.b.")
+	}
+	if resultSet["z"] {
+		t.Errorf("Tokenize(%q) = %v, abbreviation should not be split", "z.B. ein Satz.", result)
+	}
+
+	cfg.AbbreviationsPath = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := NewTokenizer(dictPath, cfg); err == nil {
+		t.Error("NewTokenizer() with a missing abbreviation list expected error")
+	}
+}
+
+func TestTokenizer_EmitEszettVariants(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.Normalizers.StemGerman = false
+	cfg.EmitEszettVariants = true
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Straße")
+	if len(result) != 2 || result[0] != "strasse" || result[1] != "straße" {
+		t.Errorf("Tokenize(%q) = %v, want [strasse straße]", "Straße", result)
+	}
+
+	// Words without ß have a single form
+	result = tok.Tokenize("Masse")
+	if len(result) != 1 || result[0] != "masse" {
+		t.Errorf("Tokenize(%q) = %v, want [masse]", "Masse", result)
+	}
+
+	// With ConvertEszett disabled the ss form is the variant
+	cfg.Normalizers.ConvertEszett = false
+	tok2, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok2.Close()
+
+	result = tok2.Tokenize("Maße")
+	if len(result) != 2 || result[0] != "maße" || result[1] != "masse" {
+		t.Errorf("Tokenize(%q) without ConvertEszett = %v, want [maße masse]", "Maße", result)
+	}
+}
+
+func TestTokenizer_BloomFilter(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.Cache = false
+	cfg.BloomFilter = true
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+
+	text := "Der Brandschutzkonzept und die Wärmedämmung der Stahlbetondecke"
+	if got, want := tok.Tokenize(text), plain.Tokenize(text); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Tokenize with bloom filter = %v, want %v", got, want)
+	}
+
+	// The filter is kept when the dictionary is replaced
+	if err := tok.ReloadDictionary(dictPath); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+	if tok.dict.bloom == nil {
+		t.Error("ReloadDictionary() dropped the bloom filter")
+	}
+}
+
+func TestTokenizer_TokenizeGrouped(t *testing.T) {
+	dictPath := getTestDictPath()
+
+	cfg := testConfig()
+	cfg.Normalizers.StemGerman = false
+
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	groups := tok.TokenizeGrouped("Das Brandschutzkonzept, das Haus.")
+
+	expected := []TokenGroup{
+		{Original: "Das", Segments: []string{"das"}},
+		{Original: "Brandschutzkonzept", Segments: []string{"brandschutzkonzept", "brand", "schutz", "konzept"}},
+		{Original: "das", Segments: []string{"das"}}, // Not deduplicated
+		{Original: "Haus", Segments: []string{"haus"}},
+	}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("TokenizeGrouped() = %v, want %d groups", groups, len(expected))
+	}
+	for i, want := range expected {
+		got := groups[i]
+		if got.Original != want.Original || strings.Join(got.Segments, "|") != strings.Join(want.Segments, "|") {
+			t.Errorf("TokenizeGrouped()[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestTokenizer_TokenizeGroupedMatchesTokenize(t *testing.T) {
+	cfg := testConfig()
+	cfg.StopWords = DefaultGermanStopWords()
+	cfg.OutputCase = OutputTitle
+
+	tok, err := NewTokenizer(getTestDictPath(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	text := "Das Brandschutzkonzept und die Wärmedämmung, das Haus."
+	var flattened []string
+	seen := make(tokenSet)
+	for _, group := range tok.TokenizeGrouped(text) {
+		if _, stop := cfg.StopWords[strings.ToLower(group.Original)]; stop {
+			t.Errorf("TokenizeGrouped(%q) has a group for stop word %q", text, group.Original)
+		}
+		for _, token := range group.Segments {
+			if seen.add(token) {
+				flattened = append(flattened, token)
+			}
+		}
+	}
+
+	if expected := tok.Tokenize(text); !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("TokenizeGrouped(%q) flattened = %v, want Tokenize = %v", text, flattened, expected)
+	}
+}
+
+func TestTokenizer_GermanNumbers(t *testing.T) {
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.GermanNumbers = true
+
+	tok, err := NewTokenizer(getTestDictPath(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("1.000,50")
+	if len(result) != 1 || result[0] != "1.000,50" {
+		t.Errorf("Tokenize(%q) = %v, want [1.000,50]", "1.000,50", result)
+	}
+}
+
+func TestTokenizer_Symbols(t *testing.T) {
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.Symbols = "€§%"
+
+	tok, err := NewTokenizer(newTestDictionary(t, "miete"), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"§ 5", []string{"§", "5"}},
+		{"20 % Miete", []string{"20", "%", "miete"}},
+		{"500€", []string{"500", "€"}},
+		// Unlisted symbols are still dropped
+		{"5 $", []string{"5"}},
+	}
+
+	for _, tt := range tests {
+		result := tok.Tokenize(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	typed := tok.TokenizeTyped("§ 5")
+	if len(typed) == 0 || typed[0] != (TypedToken{"§", KindSymbol}) {
+		t.Errorf("TokenizeTyped(%q) = %v, want § as KindSymbol first", "§ 5", typed)
+	}
+}
+
+func TestTokenizer_StemNounsOnly(t *testing.T) {
+	dictPath := newTestDictionary(t, "garten", "haben", "wagen")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.StemNounsOnly = true
+	cfg.Normalizers.StemGerman = true
+	cfg.Normalizers.Stemmer = func(s string) string { return strings.TrimSuffix(s, "en") }
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// Capitalized nouns are stemmed
+		{"Garten", []string{"gart"}},
+		{"WAGEN", []string{"wag"}},
+		// Lowercase words are not
+		{"haben", []string{"haben"}},
+		{"wir haben Garten", []string{"wir", "haben", "gart"}},
+		// Nor are function words capitalized at the start of a sentence
+		{"Haben", []string{"haben"}},
+	}
+
+	for _, tt := range tests {
+		result := tok.Tokenize(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Without the option every word is stemmed
+	cfg.StemNounsOnly = false
+	all, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer all.Close()
+	if result := all.Tokenize("haben"); !reflect.DeepEqual(result, []string{"hab"}) {
+		t.Errorf("Tokenize(%q) without StemNounsOnly = %q, want [hab]", "haben", result)
+	}
+}
+
+func TestTokenizer_SameToken(t *testing.T) {
+	dictPath := newTestDictionary(t, "größe")
+
+	tok, err := NewTokenizer(dictPath, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"Größe", "Grosse", true},
+		{"Größe", "grösse", true},
+		{"GRÖSSE", "größe", true},
+		{"Größe", "Grüße", false},
+	}
+	for _, tt := range tests {
+		if result := tok.SameToken(tt.a, tt.b); result != tt.expected {
+			t.Errorf("SameToken(%q, %q) = %v, want %v", tt.a, tt.b, result, tt.expected)
+		}
+	}
+
+	// With lowercasing only, umlauts and ß are kept apart
+	minimal, err := NewTokenizer(dictPath, Config{Normalizers: NormalizerConfig{Lowercase: true}})
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer minimal.Close()
+
+	if minimal.SameToken("Größe", "Grosse") {
+		t.Errorf("SameToken(%q, %q) with minimal config = true, want false", "Größe", "Grosse")
+	}
+	if !minimal.SameToken("Größe", "größe") {
+		t.Errorf("SameToken(%q, %q) with minimal config = false, want true", "Größe", "größe")
+	}
+}
+
+func TestTokenizer_NormalizeBeforeSplit(t *testing.T) {
+	// Dictionary stored in folded form, without umlauts or ß
+	dictPath := newTestDictionary(t, "warme", "dammung", "fuss", "ball")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = true
+	cfg.NormalizeBeforeSplit = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.Tokenize("Wärmedämmung")
+	expected := []string{"wärmedämmung", "warme", "dammung"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) = %q, want %q", "Wärmedämmung", result, expected)
+	}
+
+	// Spans map back to the source runes, also across ß→ss
+	spanTests := []struct {
+		input    string
+		expected []TokenSpan
+	}{
+		{"Wärmedämmung", []TokenSpan{
+			{"wärmedämmung", 0, 12},
+			{"warme", 0, 5},
+			{"dammung", 5, 12},
+		}},
+		{"Fußball", []TokenSpan{
+			{"fußball", 0, 7},
+			{"fuss", 0, 3},
+			{"ball", 3, 7},
+		}},
+	}
+	for _, tt := range spanTests {
+		if spans := tok.TokenizeWithSpans(tt.input); !reflect.DeepEqual(spans, tt.expected) {
+			t.Errorf("TokenizeWithSpans(%q) = %v, want %v", tt.input, spans, tt.expected)
+		}
+	}
+
+	// Decompound keeps the source spelling
+	decompounded := tok.Decompound("Wärmedämmung Fußball")
+	expectedSegments := [][]string{{"Wärme", "dämmung"}, {"Fuß", "ball"}}
+	if !reflect.DeepEqual(decompounded, expectedSegments) {
+		t.Errorf("Decompound() = %q, want %q", decompounded, expectedSegments)
+	}
+}
+
+func TestFoldUmlauts(t *testing.T) {
+	tests := []struct {
+		input   string
+		folded  string
+		offsets []int
+	}{
+		{"haus", "haus", []int{0, 1, 2, 3, 4}},
+		{"Wärme", "Warme", []int{0, 1, 2, 3, 4, 5}},
+		{"Fuß", "Fuss", []int{0, 1, 2, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		folded, offsets := foldUmlauts(tt.input)
+		if folded != tt.folded || !reflect.DeepEqual(offsets, tt.offsets) {
+			t.Errorf("foldUmlauts(%q) = %q, %v, want %q, %v", tt.input, folded, offsets, tt.folded, tt.offsets)
+		}
+	}
+}
+
+func TestTokenizer_NormalizeConfusables(t *testing.T) {
+	dictPath := newTestDictionary(t, "wärme", "dämmung", "konzept", "kern", "brand")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.Normalizers.NormalizeConfusables = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// OCR-corrupted words normalize back to dictionary hits
+		{"Wärmedärnmung", "warme|dammung"},
+		{"Brandk0nzept", "brand|konzept"},
+		// Dictionary words are never "corrected"
+		{"Kern", "kern"},
+		// Unknown words without a valid correction stay as they are
+		{"Xyzrn", "xyzrn"},
+	}
+
+	for _, tt := range tests {
+		result := strings.Join(tok.Tokenize(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Disabled by default
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+	for _, token := range plain.Tokenize("Wärmedärnmung") {
+		if token == "dammung" {
+			t.Errorf("Expected no confusable correction by default, got %v", plain.Tokenize("Wärmedärnmung"))
+		}
+	}
+
+	// The lowercase original keeps the source spelling; only the split
+	// forms are corrected
+	cfg.LowercaseOriginal = true
+	withOriginal, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer withOriginal.Close()
+	if result := strings.Join(withOriginal.Tokenize("Brandk0nzept"), "|"); result != "brandk0nzept|brand|konzept" {
+		t.Errorf("Tokenize(%q) with LowercaseOriginal = %q, want %q", "Brandk0nzept", result, "brandk0nzept|brand|konzept")
+	}
+}
+
+func TestTokenizer_TokenizeMeta(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "haus", "häuser")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.Normalizers.Stemmer = func(s string) string { return strings.TrimSuffix(s, "er") }
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []TokenMeta
+	}{
+		// Compound: both segments are dictionary hits
+		{"Brandschutz", []TokenMeta{
+			{Text: "brand", Kind: KindSegment, IsCompoundSegment: true, InDictionary: true},
+			{Text: "schutz", Kind: KindSegment, IsCompoundSegment: true, InDictionary: true},
+		}},
+		// Simple dictionary word
+		{"Haus", []TokenMeta{{Text: "haus", Kind: KindSegment, InDictionary: true}}},
+		// Simple word changed by the stemmer
+		{"Häuser", []TokenMeta{{Text: "haus", Kind: KindSegment, InDictionary: true, WasStemmed: true}}},
+		// Unknown word
+		{"Xyz", []TokenMeta{{Text: "xyz", Kind: KindSegment}}},
+	}
+
+	for _, tt := range tests {
+		result := tok.TokenizeMeta(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("TokenizeMeta(%q) = %+v, want %+v", tt.input, result, tt.expected)
+		}
+	}
+
+	// Tokens match Tokenize
+	text := "Brandschutz im Haus"
+	var texts []string
+	for _, m := range tok.TokenizeMeta(text) {
+		texts = append(texts, m.Text)
+	}
+	if !reflect.DeepEqual(texts, tok.Tokenize(text)) {
+		t.Errorf("TokenizeMeta(%q) texts = %v, want %v", text, texts, tok.Tokenize(text))
+	}
+}
+
+func TestTokenizer_StopWords(t *testing.T) {
+	dictPath := newTestDictionary(t, "wärme", "dämmung", "und", "haus", "der")
+	cfg := testConfig()
+	cfg.StopWords = DefaultGermanStopWords()
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Wärme und Dämmung", "wärme|warme|dämmung|dammung"},
+		{"UND Der", ""},
+		// Stop-word segments of a compound are skipped
+		{"Derhaus", "derhaus|haus"},
+	}
+
+	for _, tt := range tests {
+		result := strings.Join(tok.Tokenize(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Without a stop-word set all words are kept
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+	if result := strings.Join(plain.Tokenize("Wärme und"), "|"); result != "wärme|warme|und" {
+		t.Errorf("Tokenize(%q) = %q, want %q", "Wärme und", result, "wärme|warme|und")
+	}
+}
+
+func TestDefaultGermanStopWords(t *testing.T) {
+	words := DefaultGermanStopWords()
+	for _, w := range []string{"und", "der", "die", "das", "für"} {
+		if _, ok := words[w]; !ok {
+			t.Errorf("Expected %q to be a default stop word", w)
+		}
+	}
+	if _, ok := words["wärme"]; ok {
+		t.Error("Expected 'wärme' not to be a stop word")
+	}
+
+	// Each call returns an independent copy
+	delete(words, "und")
+	if _, ok := DefaultGermanStopWords()["und"]; !ok {
+		t.Error("Expected DefaultGermanStopWords to return a fresh set")
+	}
+}
+
+func TestTokenizer_Decompound(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept", "wärme", "dämmung", "häuser")
+	cfg := testConfig()
+	cfg.Normalizers.Stemmer = func(s string) string { return strings.TrimSuffix(s, "er") }
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		// Segment order preserved, source case and umlauts kept
+		{"Brandschutzkonzept", [][]string{{"Brand", "schutz", "konzept"}}},
+		{"WÄRMEDÄMMUNG", [][]string{{"WÄRME", "DÄMMUNG"}}},
+		// No stemming
+		{"Häuser", [][]string{{"Häuser"}}},
+		// No deduplication across or within words
+		{"Brandschutz, Brandschutz", [][]string{{"Brand", "schutz"}, {"Brand", "schutz"}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		result := tok.Decompound(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Decompound(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTokenizer_PassThroughNonLatin(t *testing.T) {
+	dictPath := newTestDictionary(t, "wärme", "dämmung")
+	cfg := testConfig()
+	cfg.PassThroughNonLatin = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// Cyrillic й keeps its breve instead of being decomposed and stripped
+		{"Wärmedämmung Зимний", "wärmedämmung|warme|dammung|зимний"},
+		{"ΑΘΗΝΑ", "αθηνα"},
+		{"東京", "東京"},
+		// Mostly-Latin words are processed as German
+		{"Wärme", "wärme|warme"},
+	}
+
+	for _, tt := range tests {
+		result := strings.Join(tok.Tokenize(tt.input), "|")
+		if result != tt.expected {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Disabled, Cyrillic goes through the German normalizer
+	plain, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+	if result := strings.Join(plain.Tokenize("Зимний"), "|"); result == "зимний" {
+		t.Errorf("Tokenize(%q) = %q, expected German normalization without the option", "Зимний", result)
+	}
+}
+
+func TestTokenizer_TokenFrequencies(t *testing.T) {
+	dictPath := newTestDictionary(t, "haus", "brand", "schutz")
+	tok, err := NewTokenizer(dictPath, testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	result := tok.TokenFrequencies("Haus und Haus, Brandschutz im Haus")
+	expected := map[string]int{
+		"haus":        3, // Counted once per word, not per emission
+		"und":         1,
+		"brandschutz": 1,
+		"brand":       1,
+		"schutz":      1,
+		"im":          1,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("TokenFrequencies() = %v, want %v", result, expected)
+	}
+
+	// The keys are exactly the tokens Tokenize returns
+	text := "Haus und Haus, Brandschutz im Haus"
+	for _, token := range tok.Tokenize(text) {
+		if result[token] == 0 {
+			t.Errorf("Expected token %q from Tokenize to have a frequency", token)
+		}
+	}
+	if len(result) != len(tok.Tokenize(text)) {
+		t.Errorf("TokenFrequencies() has %d tokens, Tokenize %d", len(result), len(tok.Tokenize(text)))
+	}
+}
+
+func TestTokenizer_Logger(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz")
+	var logs bytes.Buffer
+	cfg := testConfig()
+	cfg.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	if !strings.Contains(logs.String(), `msg="rebuilding FST"`) || !strings.Contains(logs.String(), "words=2") {
+		t.Errorf("Expected FST build to be logged, got %q", logs.String())
+	}
+
+	logs.Reset()
+	tok.Tokenize("Brandschutz Xyzabc")
+	if !strings.Contains(logs.String(), `msg="word not split" word=Xyzabc`) {
+		t.Errorf("Expected unsplit word to be logged at debug level, got %q", logs.String())
+	}
+	if strings.Contains(logs.String(), "Brandschutz") {
+		t.Errorf("Expected split word not to be logged, got %q", logs.String())
+	}
+
+	logs.Reset()
+	if err := tok.ReloadDictionary(dictPath); err != nil {
+		t.Fatalf("ReloadDictionary() error: %v", err)
+	}
+	if !strings.Contains(logs.String(), `msg="dictionary reloaded"`) {
+		t.Errorf("Expected reload to be logged, got %q", logs.String())
+	}
+}
+
+func TestTokenizer_DigitBoundaries(t *testing.T) {
+	dictPath := newTestDictionary(t, "typ", "stecker", "din", "format", "brand", "schutz", "klasse")
+	cfg := testConfig()
+	cfg.DigitBoundaries = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		{"Typ2Stecker", [][]string{{"Typ", "2", "Stecker"}}},
+		{"Din4Format", [][]string{{"Din", "4", "Format"}}},
+		// Letter runs are decomposed further
+		{"Brandschutz2klasse", [][]string{{"Brand", "schutz", "2", "klasse"}}},
+		// Leading and trailing digits
+		{"4Format", [][]string{{"4", "Format"}}},
+		{"A380", [][]string{{"A", "380"}}},
+		// Plain words and numbers are unaffected
+		{"Brandschutz 2024", [][]string{{"Brand", "schutz"}, {"2024"}}},
+	}
+
+	for _, tt := range tests {
+		result := tok.Decompound(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Decompound(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	expected := []string{"typ2stecker", "typ", "2", "stecker"}
+	if result := tok.Tokenize("Typ2Stecker"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) = %q, want %q", "Typ2Stecker", result, expected)
+	}
+
+	// Disabled, the splitter sees the whole word
+	cfg.DigitBoundaries = false
+	plain, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer plain.Close()
+	if result := plain.Decompound("Typ2Stecker"); !reflect.DeepEqual(result, [][]string{{"Typ2Stecker"}}) {
+		t.Errorf("Decompound(%q) without DigitBoundaries = %q, want unsplit", "Typ2Stecker", result)
+	}
+}
+
+func TestTokenizer_TokenizeInto(t *testing.T) {
+	tok, err := NewTokenizer(getTestDictPath(), testConfig())
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	texts := []string{
+		"Der Brandschutzkonzept und die Wärmedämmung",
+		"Haus und Haus",
+		"",
+		"Stahlbetondecke",
+	}
+
+	var buf []string
+	for _, text := range texts {
+		buf = tok.TokenizeInto(text, buf[:0])
+		if expected := tok.Tokenize(text); !reflect.DeepEqual(append([]string{}, buf...), append([]string{}, expected...)) {
+			t.Errorf("TokenizeInto(%q) = %v, want %v", text, buf, expected)
+		}
+	}
+
+	// Tokens are appended after existing entries, which don't deduplicate
+	result := tok.TokenizeInto("Haus", []string{"haus"})
+	if len(result) < 2 || result[0] != "haus" || result[1] != "haus" {
+		t.Errorf("TokenizeInto(%q, [haus]) = %v, want [haus haus ...]", "Haus", result)
+	}
+}
+
+func TestTokenizer_TokenFilter(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "auto", "kfz", "haus")
+
+	tests := []struct {
+		name     string
+		filter   TokenFilter
+		input    string
+		expected []string
+	}{
+		{
+			name: "drop short tokens",
+			filter: func(token string) (string, bool) {
+				return token, utf8.RuneCountInString(token) > 3
+			},
+			input:    "Brandschutz an der Tür",
+			expected: []string{"brandschutz", "brand", "schutz"},
+		},
+		{
+			name:     "uppercase",
+			filter:   func(token string) (string, bool) { return strings.ToUpper(token), true },
+			input:    "Haus haus HAUS",
+			expected: []string{"HAUS"},
+		},
+		{
+			name: "synonyms",
+			filter: func(token string) (string, bool) {
+				if token == "auto" {
+					return "kfz", true
+				}
+				return token, true
+			},
+			input:    "Auto und Kfz",
+			expected: []string{"kfz", "und"},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := testConfig()
+		cfg.TokenFilter = tt.filter
+		tok, err := NewTokenizer(dictPath, cfg)
+		if err != nil {
+			t.Fatalf("Failed to create tokenizer: %v", err)
+		}
+		if result := tok.Tokenize(tt.input); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: Tokenize(%q) = %q, want %q", tt.name, tt.input, result, tt.expected)
+		}
+		tok.Close()
+	}
+}
+
+func TestTokenizer_AlwaysEmitWhole(t *testing.T) {
+	dictPath := newTestDictionary(t, "brand", "schutz", "konzept", "wärme", "dämmung")
+	cfg := testConfig()
+	cfg.LowercaseOriginal = false
+	cfg.AlwaysEmitWhole = true
+	tok, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"Brandschutzkonzept", []string{"brandschutzkonzept", "brand", "schutz", "konzept"}},
+		// The whole form is normalized like the segments
+		{"Wärmedämmung", []string{"warmedammung", "warme", "dammung"}},
+		// Unsplit words are emitted once
+		{"Konzept", []string{"konzept"}},
+		{"Xyzabc", []string{"xyzabc"}},
+	}
+
+	for _, tt := range tests {
+		result := tok.Tokenize(tt.input)
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+
+	// Together with LowercaseOriginal, both whole forms are emitted
+	cfg.LowercaseOriginal = true
+	both, err := NewTokenizer(dictPath, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer both.Close()
+	expected := []string{"wärmedämmung", "warmedammung", "warme", "dammung"}
+	if result := both.Tokenize("Wärmedämmung"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) with LowercaseOriginal = %q, want %q", "Wärmedämmung", result, expected)
+	}
+}
+
+func TestTokenizer_DedupAcrossForms(t *testing.T) {
+	dictPath := newTestDictionary(t, "größe", "brand", "schutz", "haus")
+
+	tests := []struct {
+		input     string
+		separate  []string // DedupAcrossForms off
+		collapsed []string // DedupAcrossForms on
+	}{
+		{"Größe", []string{"größe", "grosse"}, []string{"grosse"}},
+		{"Haus", []string{"haus"}, []string{"haus"}},
+		// Compounds keep the original, which no segment stands for
+		{"Brandschutz", []string{"brandschutz", "brand", "schutz"}, []string{"brandschutz", "brand", "schutz"}},
+	}
+
+	for _, dedup := range []bool{false, true} {
+		cfg := testConfig()
+		cfg.DedupAcrossForms = dedup
+		tok, err := NewTokenizer(dictPath, cfg)
+		if err != nil {
+			t.Fatalf("Failed to create tokenizer: %v", err)
+		}
+		for _, tt := range tests {
+			expected := tt.separate
+			if dedup {
+				expected = tt.collapsed
+			}
+			if result := tok.Tokenize(tt.input); !reflect.DeepEqual(result, expected) {
+				t.Errorf("Tokenize(%q) with DedupAcrossForms=%v = %q, want %q", tt.input, dedup, result, expected)
+			}
+		}
+		tok.Close()
+	}
+
+	// With AlwaysEmitWhole the normalized whole compound replaces the original
+	cfg := testConfig()
+	cfg.DedupAcrossForms = true
+	cfg.AlwaysEmitWhole = true
+	tok, err := NewTokenizer(newTestDictionary(t, "wärme", "dämmung"), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create tokenizer: %v", err)
+	}
+	defer tok.Close()
+	expected := []string{"warmedammung", "warme", "dammung"}
+	if result := tok.Tokenize("Wärmedämmung"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Tokenize(%q) with AlwaysEmitWhole = %q, want %q", "Wärmedämmung", result, expected)
+	}
+}
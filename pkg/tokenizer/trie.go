@@ -0,0 +1,139 @@
+package tokenizer
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// PrefixLookup is a dictionary backend that reports, in a single walk, every
+// word that is a prefix of the input. CompoundSplitter uses it to find split
+// candidates without one dictionary lookup per candidate length.
+type PrefixLookup interface {
+	// WalkPrefixes calls fn with the length in runes of each dictionary word
+	// that is a prefix of runes, shortest first.
+	WalkPrefixes(runes []rune, fn func(length int))
+}
+
+// RuneTrie is an immutable in-memory rune trie over a word set.
+// It is safe for concurrent use.
+type RuneTrie struct {
+	nodes []trieNode
+	words int
+}
+
+// trieNode is a trie node; edges are sorted by rune for binary search.
+type trieNode struct {
+	edges    []trieEdge
+	terminal bool
+}
+
+// trieEdge links a node to its child for rune r.
+type trieEdge struct {
+	r    rune
+	next int32
+}
+
+// NewRuneTrie builds a trie from words.
+func NewRuneTrie(words []string) *RuneTrie {
+	t := &RuneTrie{nodes: []trieNode{{}}}
+	for _, word := range words {
+		node := int32(0)
+		for _, r := range word {
+			node = t.child(node, r, true)
+		}
+		if !t.nodes[node].terminal {
+			t.nodes[node].terminal = true
+			t.words++
+		}
+	}
+	return t
+}
+
+// child returns the child of node for rune r, or -1 if there is none.
+// With create set, a missing child is added.
+func (t *RuneTrie) child(node int32, r rune, create bool) int32 {
+	edges := t.nodes[node].edges
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].r >= r })
+	if i < len(edges) && edges[i].r == r {
+		return edges[i].next
+	}
+	if !create {
+		return -1
+	}
+
+	next := int32(len(t.nodes))
+	t.nodes = append(t.nodes, trieNode{})
+	edges = append(edges, trieEdge{})
+	copy(edges[i+1:], edges[i:])
+	edges[i] = trieEdge{r: r, next: next}
+	t.nodes[node].edges = edges
+	return next
+}
+
+// Contains reports whether word is in the trie.
+func (t *RuneTrie) Contains(word string) bool {
+	node := int32(0)
+	for _, r := range word {
+		if node = t.child(node, r, false); node < 0 {
+			return false
+		}
+	}
+	return t.nodes[node].terminal
+}
+
+// WalkPrefixes calls fn with the length of each word in the trie that is a
+// prefix of runes, shortest first.
+func (t *RuneTrie) WalkPrefixes(runes []rune, fn func(length int)) {
+	node := int32(0)
+	for i, r := range runes {
+		if node = t.child(node, r, false); node < 0 {
+			return
+		}
+		if t.nodes[node].terminal {
+			fn(i + 1)
+		}
+	}
+}
+
+// WordCount returns the number of words in the trie.
+func (t *RuneTrie) WordCount() int {
+	return t.words
+}
+
+// trieIndex keeps a RuneTrie in sync with a Dictionary, rebuilding it on
+// first use after the dictionary changes.
+type trieIndex struct {
+	dict     *Dictionary
+	mu       sync.Mutex // Serializes rebuilds
+	snapshot atomic.Pointer[trieSnapshot]
+}
+
+// trieSnapshot is a trie built from a given dictionary generation.
+type trieSnapshot struct {
+	trie       *RuneTrie
+	generation uint64
+}
+
+// newTrieIndex creates a trie index over dict. The trie is built lazily.
+func newTrieIndex(dict *Dictionary) *trieIndex {
+	return &trieIndex{dict: dict}
+}
+
+// current returns a trie matching the dictionary's current generation.
+func (ti *trieIndex) current() *RuneTrie {
+	generation := ti.dict.Generation()
+	if snap := ti.snapshot.Load(); snap != nil && snap.generation == generation {
+		return snap.trie
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	if snap := ti.snapshot.Load(); snap != nil && snap.generation == generation {
+		return snap.trie
+	}
+
+	trie := NewRuneTrie(ti.dict.Words())
+	ti.snapshot.Store(&trieSnapshot{trie: trie, generation: generation})
+	return trie
+}
@@ -0,0 +1,141 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuneTrie(t *testing.T) {
+	trie := NewRuneTrie([]string{"brand", "brandschutz", "schutz", "wärme", "brand"})
+
+	if trie.WordCount() != 4 {
+		t.Errorf("WordCount() = %d, want 4", trie.WordCount())
+	}
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"brand", true},
+		{"brandschutz", true},
+		{"wärme", true},
+		{"bran", false},
+		{"brands", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if result := trie.Contains(tt.input); result != tt.expected {
+			t.Errorf("Contains(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+
+	var lengths []int
+	trie.WalkPrefixes([]rune("brandschutzkonzept"), func(length int) {
+		lengths = append(lengths, length)
+	})
+	if len(lengths) != 2 || lengths[0] != 5 || lengths[1] != 11 {
+		t.Errorf("WalkPrefixes(%q) lengths = %v, want [5 11]", "brandschutzkonzept", lengths)
+	}
+}
+
+func TestCompoundSplitter_TrieMatchesFST(t *testing.T) {
+	dict, err := NewDictionary(getTestDictPath())
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	fst := NewCompoundSplitterNoCache(dict)
+	trie := NewCompoundSplitterNoCache(dict)
+	trie.trie = newTrieIndex(dict)
+
+	words := append(batchCorpus(), "Wärmedämmverbundsystem", "Größenordnung", "Straßenbahnhaltestelle", "Fußballweltmeisterschaft", "xyzabc")
+	for _, word := range words {
+		want := fst.Split(word)
+		got := trie.Split(word)
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("Split(%q) with trie = %v, want %v", word, got, want)
+		}
+
+		_, wantReason := fst.SplitExplain(word)
+		_, gotReason := trie.SplitExplain(word)
+		if gotReason != wantReason {
+			t.Errorf("SplitExplain(%q) with trie reason = %q, want %q", word, gotReason, wantReason)
+		}
+	}
+}
+
+func TestCompoundSplitter_TrieFollowsDictionaryChanges(t *testing.T) {
+	dict, err := NewDictionary(newTestDictionary(t, "brand", "schutz"))
+	if err != nil {
+		t.Fatalf("Failed to load components: %v", err)
+	}
+	defer dict.Close()
+
+	splitter := NewCompoundSplitterNoCache(dict)
+	splitter.trie = newTrieIndex(dict)
+
+	if result := splitter.Split("brandschutzkonzept"); len(result) != 1 {
+		t.Fatalf("Split before AddWord = %v, want unsplit", result)
+	}
+
+	if err := dict.AddWord("konzept"); err != nil {
+		t.Fatalf("AddWord() error: %v", err)
+	}
+
+	result := splitter.Split("brandschutzkonzept")
+	if len(result) != 3 {
+		t.Errorf("Split after AddWord = %v, want [brand schutz konzept]", result)
+	}
+}
+
+func TestCompoundSplitter_TrieChargesSameLookups(t *testing.T) {
+	words := []string{"ab", "brand", "schutz", "konzept", "wärme", "dämmung"}
+	set := map[string]bool{}
+	for _, word := range words {
+		set[word] = true
+	}
+	trie := NewRuneTrie(words)
+
+	inputs := []string{
+		strings.Repeat("ab", 20), "brandschutzkonzept", "brandschutzkonzepte",
+		"warmedammung", "brandxyzkonzept", "xyz", "x",
+	}
+	for _, input := range inputs {
+		const maxLookups = 10_000
+		lookup := &countingLookup{setLookup: setLookup{words: set}}
+		splitter := NewCompoundSplitterWithConfig(lookup, SplitterConfig{MaxLookups: maxLookups})
+
+		budget := splitter.newLookupBudget()
+		want, _ := splitter.greedySplit(input, budget)
+		wantCharged, wantCalls := maxLookups-budget.remaining, lookup.calls
+
+		lookup.calls = 0
+		budget = splitter.newLookupBudget()
+		got, _ := splitter.greedySplitPrefixes(input, trie, budget)
+		gotCharged := maxLookups - budget.remaining
+
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("greedySplitPrefixes(%q) = %v, want %v", input, got, want)
+		}
+		if gotCharged != wantCharged {
+			t.Errorf("greedySplitPrefixes(%q) charged %d lookups, greedySplit charged %d", input, gotCharged, wantCharged)
+		}
+		if lookup.calls > wantCalls {
+			t.Errorf("greedySplitPrefixes(%q) made %d dictionary lookups, greedySplit made %d", input, lookup.calls, wantCalls)
+		}
+	}
+
+	// The budget runs out at the same point: splitting "abab…" takes
+	// 1 + 3 + … + 299 = 22,500 probes
+	word := strings.Repeat("ab", 150)
+	for _, maxLookups := range []int{500, 22_499, 22_500} {
+		splitter := NewCompoundSplitterWithConfig(&setLookup{words: set}, SplitterConfig{MaxLookups: maxLookups})
+		want, wantStuck := splitter.greedySplit(word, splitter.newLookupBudget())
+		got, gotStuck := splitter.greedySplitPrefixes(word, trie, splitter.newLookupBudget())
+		if len(got) != len(want) || gotStuck != wantStuck {
+			t.Errorf("MaxLookups %d: greedySplitPrefixes = %d segments stuck at %d, greedySplit = %d segments stuck at %d",
+				maxLookups, len(got), gotStuck, len(want), wantStuck)
+		}
+	}
+}